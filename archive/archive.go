@@ -0,0 +1,180 @@
+// Package archive 在 go_logger 切换或压缩日志文件后，将写完的文件上传到 S3、GCS、MinIO 等对象存储。
+//
+// 本包不依赖具体的对象存储客户端，使用方用自己的客户端实现 Uploader 即可：
+//
+//	a := archive.New(uploader, archive.WithPrefix("logs/order/"), archive.WithDeleteLocal())
+//	a.Attach(logger.Default())
+//	defer a.Close()
+package archive
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+)
+
+// Uploader 将 body 上传到对象存储中的 key
+type Uploader interface {
+	Upload(ctx context.Context, key string, body io.Reader) error
+}
+
+// UploaderFunc 将普通函数转换为 Uploader
+type UploaderFunc func(ctx context.Context, key string, body io.Reader) error
+
+func (f UploaderFunc) Upload(ctx context.Context, key string, body io.Reader) error {
+	return f(ctx, key, body)
+}
+
+// Option 用于 New 的配置项
+type Option func(*Archiver)
+
+// WithPrefix 设置对象 key 的前缀，key 为前缀加上文件名
+func WithPrefix(prefix string) Option {
+	return func(a *Archiver) {
+		a.key = func(path string) string {
+			return prefix + filepath.Base(path)
+		}
+	}
+}
+
+// WithKeyFunc 设置由文件路径生成对象 key 的函数，默认使用文件名
+func WithKeyFunc(f func(path string) string) Option {
+	return func(a *Archiver) {
+		a.key = f
+	}
+}
+
+// WithDeleteLocal 上传成功后删除本地文件
+func WithDeleteLocal() Option {
+	return func(a *Archiver) {
+		a.deleteLocal = true
+	}
+}
+
+// WithTimeout 设置单个文件上传的超时时间，默认为 5 分钟
+func WithTimeout(d time.Duration) Option {
+	return func(a *Archiver) {
+		if d > 0 {
+			a.timeout = d
+		}
+	}
+}
+
+// WithErrorHandler 设置上传失败时的回调，默认忽略错误，失败的文件保留在本地
+func WithErrorHandler(h func(path string, err error)) Option {
+	return func(a *Archiver) {
+		a.onError = h
+	}
+}
+
+// Archiver 在后台 goroutine 中按顺序上传写完的日志文件
+type Archiver struct {
+	uploader    Uploader
+	key         func(path string) string
+	deleteLocal bool
+	timeout     time.Duration
+	onError     func(path string, err error)
+
+	mu      sync.Mutex
+	pending []string
+	closed  bool
+	// wake 在有新文件时通知后台 goroutine
+	wake chan struct{}
+	done chan struct{}
+}
+
+// New 创建使用 uploader 上传的 Archiver，并启动上传的 goroutine
+func New(uploader Uploader, opts ...Option) *Archiver {
+	a := &Archiver{
+		uploader: uploader,
+		key:      filepath.Base,
+		timeout:  5 * time.Minute,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	go a.run()
+	return a
+}
+
+// Attach 在 l 切换日志文件后上传写完的文件，开启压缩时等待文件被压缩后上传 .gz 文件
+func (a *Archiver) Attach(l *logger.Logger) {
+	l.OnRotate(func(oldPath, newPath string) {
+		if !l.CompressEnabled() {
+			a.Add(oldPath)
+		}
+	})
+	l.OnCompress(func(path, gzPath string) {
+		a.Add(gzPath)
+	})
+}
+
+// Add 将文件加入上传队列，Close 之后调用会被忽略
+func (a *Archiver) Add(path string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+	a.pending = append(a.pending, path)
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close 上传完队列中剩余的文件后停止后台 goroutine
+func (a *Archiver) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.wake)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+func (a *Archiver) run() {
+	defer close(a.done)
+	for range a.wake {
+		for {
+			a.mu.Lock()
+			if len(a.pending) == 0 {
+				a.mu.Unlock()
+				break
+			}
+			path := a.pending[0]
+			a.pending = a.pending[1:]
+			a.mu.Unlock()
+			if err := a.upload(path); err != nil && a.onError != nil {
+				a.onError(path, err)
+			}
+		}
+	}
+}
+
+// upload 上传一个文件，成功且设置了 WithDeleteLocal 时删除本地文件
+func (a *Archiver) upload(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	err = a.uploader.Upload(ctx, a.key(path), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || !a.deleteLocal {
+		return err
+	}
+	return os.Remove(path)
+}
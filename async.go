@@ -0,0 +1,163 @@
+package logger
+
+// OverflowPolicy 是异步队列已满时的处理方式
+type OverflowPolicy int32
+
+const (
+	// OverflowBlock 阻塞调用方直到队列有空位，不丢失日志
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest 丢弃队列中最早的日志，保留最新的日志
+	OverflowDropOldest
+	// OverflowDropNewest 丢弃正在写入的日志
+	OverflowDropNewest
+)
+
+// SetOverflowPolicy 设置默认实例异步队列已满时的处理方式
+func SetOverflowPolicy(p OverflowPolicy) {
+	std.SetOverflowPolicy(p)
+}
+
+// SetOverflowPolicy 设置异步队列已满时的处理方式，默认为 OverflowBlock，丢弃的条数记录在 Metrics 的 Dropped 中
+func (l *Logger) SetOverflowPolicy(p OverflowPolicy) {
+	l.overflowPolicy.Store(int32(p))
+}
+
+// asyncQueue 是异步模式下的日志队列，由一个后台 goroutine 按顺序写入
+type asyncQueue struct {
+	ch   chan asyncItem
+	done chan struct{}
+}
+
+// asyncItem 是队列中的一条日志或 PrintBatch 的一批日志，flushed 不为 nil 时表示一个刷新标记
+type asyncItem struct {
+	logger  *logger
+	shard   string
+	line    []byte
+	ends    []int
+	flushed chan struct{}
+}
+
+func newAsyncQueue(size int) *asyncQueue {
+	q := &asyncQueue{
+		ch:   make(chan asyncItem, size),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(q.done)
+		for item := range q.ch {
+			if item.flushed != nil {
+				close(item.flushed)
+				continue
+			}
+			item.logger.write(item.shard, item.line, item.ends)
+		}
+	}()
+	return q
+}
+
+// stop 写完队列中剩余的日志后停止后台 goroutine
+func (q *asyncQueue) stop() {
+	close(q.ch)
+	<-q.done
+}
+
+// SetAsync 设置默认实例的异步模式
+func SetAsync(bufferSize int) {
+	std.SetAsync(bufferSize)
+}
+
+// SetAsync 开启异步模式，日志放入容量为 bufferSize 的队列后由后台 goroutine 写入，队列满时按 SetOverflowPolicy 处理，默认阻塞调用方，
+// bufferSize <= 0 时关闭异步模式，关闭前会写完队列中的日志
+func (l *Logger) SetAsync(bufferSize int) {
+	l.asyncMu.Lock()
+	defer l.asyncMu.Unlock()
+	if l.async != nil {
+		l.async.stop()
+		l.async = nil
+	}
+	if bufferSize > 0 {
+		l.async = newAsyncQueue(bufferSize)
+	}
+}
+
+// enqueue 在异步模式下将日志放入队列，非异步模式返回 false，ends 的含义与 logger.write 相同
+func (l *Logger) enqueue(lv *logger, shard string, line []byte, ends []int) bool {
+	l.asyncMu.RLock()
+	defer l.asyncMu.RUnlock()
+	if l.async == nil {
+		return false
+	}
+	// line 来自缓冲池，放回之后才会被写入，需要复制一份
+	item := asyncItem{logger: lv, shard: shard, line: append([]byte(nil), line...), ends: ends}
+	switch OverflowPolicy(l.overflowPolicy.Load()) {
+	case OverflowDropNewest:
+		select {
+		case l.async.ch <- item:
+		default:
+			l.recordDropped(uint64(item.lines()))
+		}
+	case OverflowDropOldest:
+		l.async.pushDropOldest(item, l.recordDropped)
+	default:
+		l.async.ch <- item
+	}
+	return true
+}
+
+// pushDropOldest 放入 item，队列已满时丢弃最早的一条日志，刷新标记不会被丢弃
+func (q *asyncQueue) pushDropOldest(item asyncItem, dropped func(n uint64)) {
+	for {
+		select {
+		case q.ch <- item:
+			return
+		default:
+		}
+		select {
+		case old := <-q.ch:
+			if old.flushed != nil {
+				// 刷新标记之前的日志都已取出，放回队尾只会让 Flush 多等待一些日志
+				q.ch <- old
+				continue
+			}
+			dropped(uint64(old.lines()))
+		default:
+		}
+	}
+}
+
+// lines 返回 item 包含的日志条数
+func (item asyncItem) lines() int {
+	if item.ends != nil {
+		return len(item.ends)
+	}
+	return 1
+}
+
+// Flush 等待默认实例异步队列中的日志全部写入
+func Flush() {
+	std.Flush()
+}
+
+// Flush 等待异步队列中此前的日志全部写入，非异步模式下直接返回
+func (l *Logger) Flush() {
+	l.asyncMu.RLock()
+	q := l.async
+	if q == nil {
+		l.asyncMu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	q.ch <- asyncItem{flushed: flushed}
+	l.asyncMu.RUnlock()
+	<-flushed
+}
+
+// Sync 同步默认实例
+func Sync() error {
+	return std.Sync()
+}
+
+// Close 关闭默认实例
+func Close() error {
+	return std.Close()
+}
@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// auditFileName 是审计日志的文件名，审计日志不按时间轮转，也不会被 SetMaxBackups、SetMaxAge 清理
+const auditFileName = "audit.log"
+
+// genesisHash 是第一条审计日志的 prev
+var genesisHash = strings.Repeat("0", sha256.Size*2)
+
+// auditHashKey 是每行审计日志最后一个字段的开头，hash 为该行在它之前的内容的 SHA-256
+const auditHashKey = `,"hash":"`
+
+// auditReservedKeys 是审计日志自身使用的字段，With 附加的同名字段会加上 _ 前缀，避免覆盖哈希链
+var auditReservedKeys = map[string]bool{"seq": true, "ts": true, "msg": true, "prev": true, "hash": true}
+
+// auditFiles 是进程内所有打开的审计日志文件，同一路径的文件只打开一次，
+// 多个 Logger 写入同一目录时共用同一条哈希链
+var auditFiles = struct {
+	mu    sync.Mutex
+	files map[string]*auditFile
+}{files: make(map[string]*auditFile)}
+
+// auditLogger 将日志写入日志目录下单独的 audit.log，每行是一个 JSON 对象，
+// 带有递增的 seq 和上一行的 hash（prev），修改或删除任意一行都能被 VerifyAuditFile 发现，
+// 审计日志不受最低级别、采样和异步模式的影响，每次写入都会同步到磁盘，调用 Disable 之后与其他日志一样不再写入
+type auditLogger struct {
+	owner  *Logger
+	fields []Field
+	state  *auditState
+}
+
+// auditState 是同一个 Logger 的所有 auditLogger 共享的当前审计日志文件
+type auditState struct {
+	mu   sync.Mutex
+	file *auditFile
+	path string
+}
+
+// auditFile 是被引用计数的审计日志文件及其哈希链状态
+type auditFile struct {
+	mu   sync.Mutex
+	file *os.File
+	// key 是文件的绝对路径
+	key  string
+	mode os.FileMode
+	seq  uint64
+	hash string
+	// refs 是使用该文件的 auditState 数量，由 auditFiles.mu 保护
+	refs int
+}
+
+func newAuditLogger(owner *Logger) *auditLogger {
+	return &auditLogger{owner: owner, state: &auditState{}}
+}
+
+// With 附加键值对，参数按 key1, value1, key2, value2... 的顺序传入，
+// 与审计日志自身的字段 seq、ts、msg、prev、hash 同名的键会加上 _ 前缀，例如 _seq
+func (a *auditLogger) With(kv ...interface{}) *auditLogger {
+	fields := (&fieldLogger{fields: a.fields}).With(kv...).fields
+	for i := len(a.fields); i < len(fields); i++ {
+		for auditReservedKeys[fields[i].Key] {
+			fields[i].Key = "_" + fields[i].Key
+		}
+	}
+	return &auditLogger{owner: a.owner, fields: fields, state: a.state}
+}
+
+func (a *auditLogger) Println(v ...interface{}) {
+	a.output(sprintln(v))
+}
+
+func (a *auditLogger) Printf(format string, v ...interface{}) {
+	a.output(fmt.Sprintf(format, v...))
+}
+
+func (a *auditLogger) output(msg string) {
+	if disabled.Load() {
+		return
+	}
+	t := a.owner.now()
+	a.owner.mu.Lock()
+	path := filepath.Join(a.owner.dirPath, auditFileName)
+	a.owner.mu.Unlock()
+	s := a.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.open(path, a.owner.getFileMode()); err != nil {
+		a.owner.reportError(nil, err)
+		return
+	}
+	f := s.file
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `{"seq":%d,"ts":`, f.seq+1)
+	writeJSONString(&b, t.Format(defaultJSONTimeFormat))
+	b.WriteString(`,"msg":`)
+	writeJSONString(&b, msg)
+	writeJSONFields(&b, a.fields)
+	b.WriteString(`,"prev":`)
+	writeJSONString(&b, f.hash)
+	sum := sha256.Sum256(b.Bytes())
+	hash := hex.EncodeToString(sum[:])
+	b.WriteString(auditHashKey + hash + "\"}\n")
+	if _, err := f.Write(b.Bytes()); err != nil {
+		a.owner.reportError(f, err)
+		return
+	}
+	f.seq++
+	f.hash = hash
+}
+
+// Write 写入并同步一行审计日志，调用方需持有 f.mu
+func (f *auditFile) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.file.Sync()
+}
+
+// open 切换到审计日志文件 path，已打开的是同一个文件时直接返回，调用方需持有 s.mu
+func (s *auditState) open(path string, mode os.FileMode) error {
+	if s.file != nil && s.path == path {
+		return nil
+	}
+	s.closeFile()
+	file, err := openAuditFile(path, mode)
+	if err != nil {
+		return err
+	}
+	s.file, s.path = file, path
+	return nil
+}
+
+func (s *auditState) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.release()
+	s.file = nil
+	return err
+}
+
+func (s *auditState) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeFile()
+}
+
+// reopen 重新打开正在写入的审计日志文件，尚未打开时不做任何事
+func (s *auditState) reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	return s.file.reopen()
+}
+
+// openAuditFile 返回 path 对应的共享审计日志文件，尚未打开时打开并从最后一行恢复 seq 和 hash
+func openAuditFile(path string, mode os.FileMode) (*auditFile, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+	auditFiles.mu.Lock()
+	defer auditFiles.mu.Unlock()
+	if f := auditFiles.files[key]; f != nil {
+		f.refs++
+		return f, nil
+	}
+	f := &auditFile{key: key, mode: mode, refs: 1}
+	if err := f.openLocked(); err != nil {
+		return nil, err
+	}
+	auditFiles.files[key] = f
+	return f, nil
+}
+
+// openLocked 打开文件并从最后一行恢复 seq 和 hash，调用方需持有 f.mu 或者 f 尚未被共享
+func (f *auditFile) openLocked() error {
+	seq, hash, err := lastAuditRecord(f.key)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.key, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.mode)
+	if err != nil {
+		return fmt.Errorf("打开审计日志文件失败：%w", err)
+	}
+	f.file, f.seq, f.hash = file, seq, hash
+	return nil
+}
+
+// reopen 关闭文件描述符并重新打开同一路径，文件被外部工具移动后从新文件的最后一行恢复哈希链
+func (f *auditFile) reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	old := f.file
+	if err := f.openLocked(); err != nil {
+		return err
+	}
+	return old.Close()
+}
+
+// release 释放对 f 的引用，没有其他使用者时关闭文件
+func (f *auditFile) release() error {
+	auditFiles.mu.Lock()
+	defer auditFiles.mu.Unlock()
+	f.refs--
+	if f.refs > 0 {
+		return nil
+	}
+	delete(auditFiles.files, f.key)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// auditRecord 是校验时需要的字段
+type auditRecord struct {
+	Seq  uint64 `json:"seq"`
+	Prev string `json:"prev"`
+	Hash string `json:"hash"`
+}
+
+// lastAuditRecord 返回 path 最后一行的 seq 和 hash，文件不存在或为空时返回 0 和 genesisHash
+func lastAuditRecord(path string) (uint64, string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, genesisHash, nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("读取审计日志文件失败：%w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, "", fmt.Errorf("读取审计日志文件失败：%w", err)
+	}
+	// 只读取文件末尾的一段，单行审计日志不应超过 1MB
+	const tail = 1 << 20
+	offset := info.Size() - tail
+	if offset < 0 {
+		offset = 0
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return 0, "", fmt.Errorf("读取审计日志文件失败：%w", err)
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return 0, genesisHash, nil
+	}
+	last := data[bytes.LastIndexByte(data, '\n')+1:]
+	var r auditRecord
+	if err := json.Unmarshal(last, &r); err != nil || r.Hash == "" {
+		return 0, "", fmt.Errorf("审计日志文件最后一行已损坏：%s", path)
+	}
+	return r.Seq, r.Hash, nil
+}
+
+// VerifyAuditFile 校验审计日志文件的哈希链，返回第一处不一致的行
+func VerifyAuditFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	prev, seq := genesisHash, uint64(0)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		i := bytes.LastIndex(data, []byte(auditHashKey))
+		var r auditRecord
+		if i < 0 || json.Unmarshal(data, &r) != nil {
+			return fmt.Errorf("审计日志第 %d 行格式错误", line)
+		}
+		sum := sha256.Sum256(data[:i])
+		switch {
+		case r.Seq != seq+1:
+			return fmt.Errorf("审计日志第 %d 行的 seq 为 %d，应为 %d", line, r.Seq, seq+1)
+		case r.Prev != prev:
+			return fmt.Errorf("审计日志第 %d 行的 prev 与上一行的 hash 不一致", line)
+		case r.Hash != hex.EncodeToString(sum[:]):
+			return fmt.Errorf("审计日志第 %d 行的 hash 校验失败", line)
+		}
+		prev, seq = r.Hash, r.Seq
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditRespectsDisable(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithDir(dir))
+	defer l.Close()
+	path := filepath.Join(dir, auditFileName)
+	Disable()
+	l.Audit.Println("while disabled")
+	Enable()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Disable 之后不应当写入审计日志：%v", err)
+	}
+	l.Audit.Println("after enable")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); strings.Contains(got, "while disabled") || !strings.Contains(got, "after enable") || strings.Count(got, "\n") != 1 {
+		t.Errorf("审计日志的内容为 %q，应当只有 Enable 之后的一条", got)
+	}
+	if err := VerifyAuditFile(path); err != nil {
+		t.Errorf("哈希链校验失败：%v", err)
+	}
+}
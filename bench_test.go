@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// newBenchLogger 返回只写入 io.Discard 的实例，测量编码和写入路径本身的开销
+func newBenchLogger(b *testing.B, f Format) *Logger {
+	l := New(WithDir(b.TempDir()), WithWriters(io.Discard), WithFormat(f))
+	l.DisableFileOutput()
+	b.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+var benchFormats = []struct {
+	name   string
+	format Format
+}{
+	{"text", FormatText},
+	{"json", FormatJSON},
+	{"logfmt", FormatLogfmt},
+}
+
+func BenchmarkPrintln(b *testing.B) {
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info.Println("request handled", i)
+			}
+		})
+	}
+}
+
+func BenchmarkPrintf(b *testing.B) {
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info.Printf("request %d handled in %s", i, time.Millisecond)
+			}
+		})
+	}
+}
+
+func BenchmarkWith(b *testing.B) {
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info.With("method", "GET", "path", "/orders", "status", 200, "latency", time.Millisecond).Println("request handled")
+			}
+		})
+	}
+}
+
+func BenchmarkWithFields(b *testing.B) {
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info.WithFields(String("method", "GET"), String("path", "/orders"), Int("status", 200), Duration("latency", time.Millisecond)).Println("request handled")
+			}
+		})
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	err := errors.New("connection refused")
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Error.Println("query failed", err)
+			}
+		})
+	}
+}
+
+// BenchmarkParallel 测量大量 goroutine 同时写入时的竞争
+func BenchmarkParallel(b *testing.B) {
+	for _, f := range benchFormats {
+		b.Run(f.name, func(b *testing.B) {
+			l := newBenchLogger(b, f.format)
+			b.ReportAllocs()
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.Info.With("status", 200).Println("request handled")
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkFile 测量写入日志文件的完整路径，包括轮转检查和文件写入
+func BenchmarkFile(b *testing.B) {
+	l := New(WithDir(b.TempDir()))
+	b.Cleanup(func() { _ = l.Close() })
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info.Println("request handled")
+		}
+	})
+}
+
+// BenchmarkDisabledLevel 测量低于最低级别的日志被丢弃的开销
+func BenchmarkDisabledLevel(b *testing.B) {
+	l := newBenchLogger(b, FormatText)
+	l.SetLevel(LevelInfo)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug.Println("cache miss", i)
+	}
+}
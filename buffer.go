@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer 是放回缓冲池的缓冲区的最大容量，避免个别很长的日志长期占用内存
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer 从缓冲池中取出一个空的缓冲区
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer 将缓冲区放回缓冲池
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
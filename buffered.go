@@ -0,0 +1,34 @@
+package logger
+
+import "time"
+
+// SetBuffered 设置默认实例写入日志文件的缓冲
+func SetBuffered(size int, flushInterval time.Duration) {
+	std.SetBuffered(size, flushInterval)
+}
+
+// SetBuffered 开启日志文件的写缓冲，日志先放入每个文件最多 size 字节的缓冲区，缓冲区满、每隔 flushInterval
+// 以及 Sync、Close、Fatal 时写入文件，以减少高频写入时的系统调用，size <= 0 时关闭缓冲并写入缓冲区中的日志，
+// 附加的 writer 不受影响，SyncEveryWrite 策略下不缓冲
+func (l *Logger) SetBuffered(size int, flushInterval time.Duration) {
+	if size < 0 {
+		size = 0
+	}
+	l.bufferSize.Store(int64(size))
+	var s *syncer
+	if size > 0 && flushInterval > 0 {
+		s = l.startSyncer(flushInterval, func(sink *fileSink) error {
+			return sink.flush()
+		})
+	}
+	if old := l.flusher.Swap(s); old != nil {
+		old.stop()
+	}
+	if size == 0 {
+		for _, sink := range l.sinks() {
+			if err := sink.flush(); err != nil {
+				l.reportError(nil, err)
+			}
+		}
+	}
+}
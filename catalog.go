@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageIDKey 是 Msg 输出的消息 ID 字段名
+const MessageIDKey = "msg_id"
+
+// MessageCatalog 根据消息 ID 查找消息模板，可以按语言提供不同的实现
+type MessageCatalog interface {
+	Lookup(id string) (template string, ok bool)
+}
+
+// Messages 是以 map 实现的 MessageCatalog，key 为消息 ID，value 为消息模板
+type Messages map[string]string
+
+func (m Messages) Lookup(id string) (string, bool) {
+	template, ok := m[id]
+	return template, ok
+}
+
+// SetMessageCatalog 设置默认实例的消息模板
+func SetMessageCatalog(c MessageCatalog) {
+	std.SetMessageCatalog(c)
+}
+
+// SetMessageCatalog 设置 Msg 使用的消息模板，模板中的 {key} 替换为同名字段的值，例如：
+//
+//	logger.SetMessageCatalog(logger.Messages{"USER_LOGIN_FAILED": "用户 {user} 登录失败"})
+//	logger.Warning.Msg("USER_LOGIN_FAILED", "user", "alice", "ip", ip)
+//
+// c 为 nil 时取消，之后 Msg 以消息 ID 作为日志内容
+func (l *Logger) SetMessageCatalog(c MessageCatalog) {
+	if c == nil {
+		l.catalog.Store(nil)
+	} else {
+		l.catalog.Store(&c)
+	}
+}
+
+// Msg 按消息 ID 输出日志，日志内容为模板替换字段后的文本，并以 msg_id 字段记录 ID，之后是 kv 中的字段，
+// 用于在修改日志措辞时保持稳定的、机器可读的事件编码，kv 的格式与 With 相同，模板中不存在的 ID 以 ID 本身作为日志内容
+func (l *logger) Msg(id string, kv ...interface{}) {
+	(&fieldLogger{logger: l}).Msg(id, kv...)
+}
+
+func (l *fieldLogger) Msg(id string, kv ...interface{}) {
+	if !l.Enabled() {
+		return
+	}
+	o := l.With(append([]interface{}{MessageIDKey, id}, kv...)...)
+	o.Println(l.logger.owner.renderMessage(id, o.fields))
+}
+
+// renderMessage 使用 fields 替换消息 ID 对应模板中的 {key}，没有对应的字段时保留原样
+func (l *Logger) renderMessage(id string, fields []Field) string {
+	c := l.catalog.Load()
+	if c == nil {
+		return id
+	}
+	template, ok := (*c).Lookup(id)
+	if !ok {
+		return id
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		key := template[start+1 : end]
+		if v, ok := lookupField(fields, key); ok {
+			fmt.Fprint(&b, fieldValue(v))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// lookupField 返回 fields 中最后一个键为 key 的字段的值
+func lookupField(fields []Field, key string) (interface{}, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return fields[i].Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumExt 是校验文件的扩展名，例如 2006-01-02.info.log.sha256
+const checksumExt = ".sha256"
+
+// ErrChecksumMismatch 是日志文件与校验文件中记录的 SHA-256 不一致时的错误，文件被截断或修改时返回
+var ErrChecksumMismatch = errors.New("日志文件与校验文件不一致")
+
+// SetChecksums 设置默认实例是否为历史日志文件生成校验文件
+func SetChecksums(enabled bool) {
+	std.SetChecksums(enabled)
+}
+
+// SetChecksums 设置是否在日志文件轮转或切分后为写完的文件生成 SHA-256 校验文件，例如 2006-01-02.info.log.sha256，
+// 格式与 sha256sum 相同，压缩后改为记录 .gz 文件，清理历史文件时一并删除，使用 VerifyArchive 检查历史文件是否被截断或修改，
+// 校验文件在后台 goroutine 中生成，先于 OnRotate 的回调
+func (l *Logger) SetChecksums(enabled bool) {
+	l.checksums.Store(enabled)
+}
+
+// recordChecksum 为写完的文件 path 生成校验文件，replaced 不为空时是被 path 替换的文件（压缩前的文件），删除其校验文件
+func (l *Logger) recordChecksum(path, replaced string) {
+	if !l.checksums.Load() {
+		return
+	}
+	if replaced != "" {
+		_ = os.Remove(replaced + checksumExt)
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		// 文件可能已经被压缩，压缩后会再记录 .gz 文件
+		if !os.IsNotExist(err) {
+			l.reportError(nil, err)
+		}
+		return
+	}
+	line := sum + "  " + filepath.Base(path) + "\n"
+	if err := os.WriteFile(path+checksumExt, []byte(line), l.getFileMode()); err != nil {
+		l.reportError(nil, err)
+	}
+}
+
+// fileSHA256 返回文件内容的 SHA-256，十六进制编码
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyResult 是 VerifyArchive 检查一个日志文件的结果
+type VerifyResult struct {
+	// Path 是日志文件的路径
+	Path string
+	// Err 为 nil 表示文件与校验文件一致，文件被截断或修改时为 ErrChecksumMismatch，文件不存在时满足 os.IsNotExist
+	Err error
+}
+
+// VerifyArchive 检查目录 dir 及其子目录中所有校验文件记录的日志文件，返回每个文件的结果，
+// 没有校验文件的文件（例如正在写入的文件）不检查，只有读取目录失败时返回 error
+func VerifyArchive(dir string) ([]VerifyResult, error) {
+	var results []VerifyResult
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, checksumExt) {
+			return nil
+		}
+		results = append(results, verifyChecksumFile(path)...)
+		return nil
+	})
+	return results, err
+}
+
+// verifyChecksumFile 检查校验文件 path 中的每一行
+func verifyChecksumFile(path string) []VerifyResult {
+	f, err := os.Open(path)
+	if err != nil {
+		return []VerifyResult{{Path: strings.TrimSuffix(path, checksumExt), Err: err}}
+	}
+	defer f.Close()
+	var results []VerifyResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok {
+			results = append(results, VerifyResult{Path: path, Err: fmt.Errorf("校验文件格式错误：%s", line)})
+			continue
+		}
+		// sha256sum 的二进制模式在文件名前加 *
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*")
+		target := filepath.Join(filepath.Dir(path), name)
+		r := VerifyResult{Path: target}
+		if actual, err := fileSHA256(target); err != nil {
+			r.Err = err
+		} else if !strings.EqualFold(actual, sum) {
+			r.Err = ErrChecksumMismatch
+		}
+		results = append(results, r)
+	}
+	if err := scanner.Err(); err != nil {
+		results = append(results, VerifyResult{Path: path, Err: err})
+	}
+	return results
+}
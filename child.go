@@ -0,0 +1,29 @@
+package logger
+
+// With 返回默认实例附加了键值对的一组日志记录器
+func With(kv ...interface{}) *contextLogger {
+	return std.With(kv...)
+}
+
+// With 返回附加了键值对的一组子日志记录器，之后输出的每条日志都带有这些键值对，
+// 参数按 key1, value1, key2, value2... 的顺序传入，子日志记录器可以继续调用 With 组合更多的键值对
+func (l *Logger) With(kv ...interface{}) *contextLogger {
+	return &contextLogger{
+		Trace:   l.Trace.With(kv...),
+		Debug:   l.Debug.With(kv...),
+		Info:    l.Info.With(kv...),
+		Warning: l.Warning.With(kv...),
+		Error:   l.Error.With(kv...),
+	}
+}
+
+// With 返回在当前键值对之后追加了 kv 的一组子日志记录器
+func (l *contextLogger) With(kv ...interface{}) *contextLogger {
+	return &contextLogger{
+		Trace:   l.Trace.With(kv...),
+		Debug:   l.Debug.With(kv...),
+		Info:    l.Info.With(kv...),
+		Warning: l.Warning.With(kv...),
+		Error:   l.Error.With(kv...),
+	}
+}
@@ -0,0 +1,28 @@
+package logger
+
+import "time"
+
+// SetClock 设置默认实例获取当前时间的函数
+func SetClock(clock func() time.Time) {
+	std.SetClock(clock)
+}
+
+// SetClock 设置该实例获取当前时间的函数，日志的时间戳、日志文件名中的日期以及按时间的轮转和清理都使用它，
+// 用于在测试中固定时间戳或者模拟跨天，优先于 SetClockForTesting，传入 nil 恢复使用 SetClockForTesting 设置的时钟
+func (l *Logger) SetClock(clock func() time.Time) {
+	if clock == nil {
+		l.clock.Store(nil)
+	} else {
+		l.clock.Store(&clock)
+	}
+	l.rotateIfChanged()
+}
+
+// WithClock 设置获取当前时间的函数，见 Logger.SetClock
+func WithClock(clock func() time.Time) Option {
+	return func(l *Logger) {
+		if clock != nil {
+			l.clock.Store(&clock)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nickham-su/go_logger"
+)
+
+// CBOR（RFC 8949）的主类型
+const (
+	cborUint   = 0 << 5
+	cborNegint = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborSimple = 7 << 5
+)
+
+// writeCBOR 将 e 编码为一个 CBOR map，多条日志依次拼接为 CBOR 序列（RFC 8742）
+func writeCBOR(w *bufio.Writer, e *logger.Entry) error {
+	fields := entryFields(e)
+	cborHead(w, cborMap, uint64(len(fields)))
+	for _, f := range fields {
+		cborHead(w, cborText, uint64(len(f.key)))
+		w.WriteString(f.key)
+		cborValue(w, f.value)
+	}
+	return nil
+}
+
+// cborHead 写入主类型 major 和参数 n
+func cborHead(w *bufio.Writer, major byte, n uint64) {
+	var b [9]byte
+	switch {
+	case n < 24:
+		w.WriteByte(major | byte(n))
+		return
+	case n <= math.MaxUint8:
+		b[0], b[1] = major|24, byte(n)
+		w.Write(b[:2])
+	case n <= math.MaxUint16:
+		b[0] = major | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		w.Write(b[:3])
+	case n <= math.MaxUint32:
+		b[0] = major | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		w.Write(b[:5])
+	default:
+		b[0] = major | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		w.Write(b[:9])
+	}
+}
+
+// cborValue 编码解析得到的字段值，其他类型编码为 fmt.Sprint 的文本
+func cborValue(w *bufio.Writer, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		w.WriteByte(cborSimple | 22)
+	case bool:
+		if v {
+			w.WriteByte(cborSimple | 21)
+		} else {
+			w.WriteByte(cborSimple | 20)
+		}
+	case int64:
+		if v >= 0 {
+			cborHead(w, cborUint, uint64(v))
+		} else {
+			cborHead(w, cborNegint, uint64(-(v + 1)))
+		}
+	case float64:
+		var b [9]byte
+		b[0] = cborSimple | 27
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(v))
+		w.Write(b[:])
+	case string:
+		cborHead(w, cborText, uint64(len(v)))
+		w.WriteString(v)
+	case []byte:
+		cborHead(w, cborBytes, uint64(len(v)))
+		w.Write(v)
+	case []interface{}:
+		cborHead(w, cborArray, uint64(len(v)))
+		for _, item := range v {
+			cborValue(w, item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		cborHead(w, cborMap, uint64(len(keys)))
+		for _, k := range keys {
+			cborHead(w, cborText, uint64(len(k)))
+			w.WriteString(k)
+			cborValue(w, v[k])
+		}
+	default:
+		cborValue(w, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,197 @@
+// go_logger-cat 读取本包写入的日志文件，按级别和时间范围过滤后转换为 NDJSON（每行一个 JSON 对象）或 CBOR 输出到标准输出，
+// 用于将日志导入其他系统，支持文本、JSON、logfmt 格式以及 gzip 压缩和加密后的文件：
+//
+//	go_logger-cat -level warning -since 2h logs/2006-01-02.info.log
+//	go_logger-cat -since 2006-01-02T00:00:00+08:00 -until 2006-01-03T00:00:00+08:00 -format cbor logs/*.log.gz > logs.cbor
+//
+// 每条日志输出 ts、level、module、msg、caller、error、stack 以及其他字段，空的内置字段省略
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+)
+
+func main() {
+	levelName := flag.String("level", "", "最低级别，例如 warning，默认输出所有级别")
+	since := flag.String("since", "", "只输出此时间及之后的日志，RFC3339 格式的时间或者距现在的时长，例如 2h")
+	until := flag.String("until", "", "只输出此时间之前的日志，格式与 -since 相同")
+	format := flag.String("format", "ndjson", "输出格式：ndjson 或 cbor")
+	timeFormat := flag.String("time-format", "", "写入时 SetTimeFormat 设置的时间格式，默认为各格式默认的时间格式")
+	keyHex := flag.String("key", os.Getenv("LOGGER_ENCRYPTION_KEY"), "十六进制编码的密钥，用于读取加密的日志文件")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "用法：go_logger-cat [选项] 文件...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f := filter{level: logger.LevelTrace}
+	var err error
+	if *levelName != "" {
+		if f.level, err = logger.ParseLevel(*levelName); err != nil {
+			fail(2, err)
+		}
+	}
+	if f.since, err = parseTime(*since); err != nil {
+		fail(2, err)
+	}
+	if f.until, err = parseTime(*until); err != nil {
+		fail(2, err)
+	}
+	var encode func(w *bufio.Writer, e *logger.Entry) error
+	switch strings.ToLower(*format) {
+	case "ndjson", "json":
+		encode = writeJSON
+	case "cbor":
+		encode = writeCBOR
+	default:
+		fail(2, fmt.Errorf("未知的输出格式：%q", *format))
+	}
+	var opts []logger.ParseOption
+	if *timeFormat != "" {
+		opts = append(opts, logger.WithParseTimeFormat(*timeFormat))
+	}
+	if s := strings.TrimSpace(*keyHex); s != "" {
+		key, err := hex.DecodeString(s)
+		if err != nil {
+			fail(2, fmt.Errorf("密钥必须是十六进制编码的 16、24 或 32 字节"))
+		}
+		opts = append(opts, logger.WithParseKey(key))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for _, path := range flag.Args() {
+		if err := cat(out, path, f, encode, opts); err != nil {
+			out.Flush()
+			fail(1, fmt.Errorf("%s：%w", path, err))
+		}
+	}
+}
+
+// filter 是日志的过滤条件，since、until 为零值时不限制
+type filter struct {
+	level        logger.Level
+	since, until time.Time
+}
+
+func (f filter) match(e *logger.Entry) bool {
+	if e.Level.Rank() < f.level.Rank() {
+		return false
+	}
+	if !f.since.IsZero() && e.Time.Before(f.since) {
+		return false
+	}
+	return f.until.IsZero() || e.Time.Before(f.until)
+}
+
+// cat 读取文件 path 中的日志，将满足 f 的日志编码后写入 out
+func cat(out *bufio.Writer, path string, f filter, encode func(*bufio.Writer, *logger.Entry) error, opts []logger.ParseOption) error {
+	file, err := logger.OpenLogFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	p, err := logger.NewParser(file, opts...)
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !f.match(&e) {
+			continue
+		}
+		if err := encode(out, &e); err != nil {
+			return err
+		}
+	}
+}
+
+// parseTime 解析 RFC3339 格式的时间，或者距现在的时长，空字符串返回零值
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("时间格式错误：%q，应当为 RFC3339 格式的时间或者时长", s)
+	}
+	return t, nil
+}
+
+// field 是输出的一个键值对
+type field struct {
+	key   string
+	value interface{}
+}
+
+// entryFields 按输出顺序返回 e 的所有字段，空的内置字段省略
+func entryFields(e *logger.Entry) []field {
+	fields := []field{
+		{"ts", e.Time.Format(time.RFC3339Nano)},
+		{"level", e.Level.String()},
+	}
+	add := func(key, value string) {
+		if value != "" {
+			fields = append(fields, field{key, value})
+		}
+	}
+	add("module", e.Module)
+	add("msg", e.Message)
+	add("caller", e.Caller)
+	if e.Err != nil {
+		add("error", e.Err.Error())
+	}
+	add("stack", e.Stack)
+	for _, f := range e.Fields {
+		fields = append(fields, field{f.Key, f.Value})
+	}
+	return fields
+}
+
+// writeJSON 将 e 编码为一行 JSON，字段保持 entryFields 的顺序
+func writeJSON(w *bufio.Writer, e *logger.Entry) error {
+	w.WriteByte('{')
+	for i, f := range entryFields(e) {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		w.Write(key)
+		w.WriteByte(':')
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(f.value))
+		}
+		w.Write(value)
+	}
+	w.WriteString("}\n")
+	return nil
+}
+
+func fail(code int, err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(code)
+}
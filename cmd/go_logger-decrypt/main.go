@@ -0,0 +1,44 @@
+// go_logger-decrypt 解密 SetEncryptionKey 加密的日志文件并输出到标准输出，支持 gzip 压缩后的文件：
+//
+//	go_logger-decrypt -key 00112233... logs/2006-01-02.info.log logs/2006-01-01.info.log.gz
+//
+// 未指定 -key 时读取环境变量 LOGGER_ENCRYPTION_KEY，密钥为十六进制编码
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nickham-su/go_logger"
+)
+
+func main() {
+	keyHex := flag.String("key", os.Getenv("LOGGER_ENCRYPTION_KEY"), "十六进制编码的密钥")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "用法：go_logger-decrypt [-key 密钥] 文件...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(*keyHex))
+	if err != nil || len(key) == 0 {
+		fmt.Fprintln(os.Stderr, "密钥必须是十六进制编码的 16、24 或 32 字节")
+		os.Exit(2)
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for _, path := range flag.Args() {
+		if err := logger.DecryptFile(path, key, out); err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "%s：%v\n", path, err)
+			os.Exit(1)
+		}
+	}
+}
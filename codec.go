@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Codec 是日志文件的压缩格式，内置 gzip，其他格式可以基于第三方库实现，例如 zstd：
+//
+//	type zstdCodec struct{}
+//
+//	func (zstdCodec) Extension() string { return ".zst" }
+//
+//	func (zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }
+//
+//	func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+//		d, err := zstd.NewReader(r)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return d.IOReadCloser(), nil
+//	}
+//
+//	logger.SetCodec(zstdCodec{})
+type Codec interface {
+	// Extension 返回压缩后的文件追加的扩展名，例如 .gz、.zst，应当以 . 开头且各格式互不相同
+	Extension() string
+	// NewWriter 返回将压缩后的数据写入 w 的 io.WriteCloser，Close 时写完剩余的数据但不关闭 w，
+	// 实现了 Flush() error 时流式压缩会在同步文件前调用
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader 返回解压 r 的 io.ReadCloser，需要能够读取多段压缩数据依次拼接而成的文件
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCodec 返回 gzip 格式的 Codec，level 为 compress/gzip 的压缩级别，例如 gzip.BestSpeed
+func GzipCodec(level int) Codec {
+	return gzipCodec{level: level}
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func (gzipCodec) Extension() string {
+	return ".gz"
+}
+
+func (c gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, c.level)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// defaultCodec 是未调用 SetCodec 时使用的压缩格式
+var defaultCodec = GzipCodec(gzip.DefaultCompression)
+
+// codecs 是已注册的压缩格式，key 为扩展名
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{defaultCodec.Extension(): defaultCodec}
+)
+
+// RegisterCodec 注册压缩格式，使历史文件的清理、Tail 和 ParseFile 能够识别该扩展名的文件，
+// SetCodec 会自动注册，只读取其他进程写入的文件时需要调用，同一扩展名后注册的覆盖之前的
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[c.Extension()] = c
+}
+
+// codecFor 返回文件名 name 的扩展名对应的压缩格式，多个扩展名匹配时（例如 .gz 和 .tar.gz）使用最长的，不是压缩文件时返回 nil
+func codecFor(name string) Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	var found Codec
+	longest := 0
+	for ext, c := range codecs {
+		if len(ext) > longest && strings.HasSuffix(name, ext) {
+			found, longest = c, len(ext)
+		}
+	}
+	return found
+}
+
+// trimCodecExt 去掉文件名中压缩格式的扩展名，返回去掉的扩展名，不是压缩文件时返回空字符串
+func trimCodecExt(name string) (string, string) {
+	if c := codecFor(name); c != nil {
+		ext := c.Extension()
+		return strings.TrimSuffix(name, ext), ext
+	}
+	return name, ""
+}
+
+// codecPattern 返回匹配所有已注册的压缩扩展名的正则表达式，例如 (?:\.zst|\.gz)?，较长的扩展名排在前面，与 codecFor 一致
+func codecPattern() string {
+	codecsMu.RLock()
+	exts := make([]string, 0, len(codecs))
+	for ext := range codecs {
+		exts = append(exts, ext)
+	}
+	codecsMu.RUnlock()
+	sort.Slice(exts, func(i, j int) bool {
+		if len(exts[i]) != len(exts[j]) {
+			return len(exts[i]) > len(exts[j])
+		}
+		return exts[i] < exts[j]
+	})
+	for i, ext := range exts {
+		exts[i] = regexp.QuoteMeta(ext)
+	}
+	return "(?:" + strings.Join(exts, "|") + ")?"
+}
+
+// SetCodec 设置默认实例的压缩格式
+func SetCodec(c Codec) {
+	std.SetCodec(c)
+}
+
+// SetCodec 设置 SetCompress 压缩历史文件以及 SetStreamCompression 使用的压缩格式，默认为 gzip，传入 nil 恢复默认，
+// 已经压缩的文件不会转换，开启流式压缩时立即切换到新扩展名的文件
+func (l *Logger) SetCodec(c Codec) {
+	if c == nil {
+		c = defaultCodec
+	}
+	RegisterCodec(c)
+	l.codec.Store(&c)
+	if l.streamCompress.Load() {
+		l.rotate()
+	}
+}
+
+// getCodec 返回当前的压缩格式
+func (l *Logger) getCodec() Codec {
+	if c := l.codec.Load(); c != nil {
+		return *c
+	}
+	return defaultCodec
+}
+
+// SetStreamCompression 设置默认实例是否流式压缩正在写入的日志文件
+func SetStreamCompression(enabled bool) {
+	std.SetStreamCompression(enabled)
+}
+
+// SetStreamCompression 设置是否在写入时直接压缩日志文件，例如写入 2006-01-02.info.log.gz，
+// 避免轮转后再压缩带来的一次额外读写，适用于日志量很大的服务，开启后立即切换到新的文件，
+// 压缩器中的数据在 SetSyncPolicy 同步、SetBuffered 刷新、Flush 和关闭文件时写入文件，进程崩溃时会丢失尚未写入的部分，
+// 每次打开文件时开始新的一段压缩数据，因此重启后可以继续追加，SetMaxFileSize 按压缩后的大小计算，
+// 不支持多个进程写入同一文件（SetFileLock），与 SetEncryptionKey 同时使用时压缩加密后的记录
+func (l *Logger) SetStreamCompression(enabled bool) {
+	if l.streamCompress.Swap(enabled) != enabled {
+		l.rotate()
+	}
+}
+
+// streamCodec 返回流式压缩使用的压缩格式，未开启时返回 nil
+func (l *Logger) streamCodec() Codec {
+	if !l.streamCompress.Load() {
+		return nil
+	}
+	return l.getCodec()
+}
+
+// sharedFileWriter 将压缩器的输出写入文件
+type sharedFileWriter struct {
+	f *sharedFile
+}
+
+func (w sharedFileWriter) Write(p []byte) (int, error) {
+	return w.f.write(p, false)
+}
+
+// startStream 开始以 c 流式压缩写入 f，已经开始时不做任何事
+func (f *sharedFile) startStream(c Codec) error {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	if f.stream != nil {
+		return nil
+	}
+	w, err := c.NewWriter(sharedFileWriter{f})
+	if err != nil {
+		return err
+	}
+	f.stream, f.codec = w, c
+	return nil
+}
+
+// writeStream 将 p 写入压缩器
+func (f *sharedFile) writeStream(p []byte) (int, error) {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	if f.stream == nil {
+		return f.write(p, false)
+	}
+	return f.stream.Write(p)
+}
+
+// flushStream 将压缩器中的数据写入文件，压缩器不支持 Flush 时不做任何事
+func (f *sharedFile) flushStream() error {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	if flusher, ok := f.stream.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// closeStream 写完压缩器中剩余的数据，结束当前这一段压缩数据
+func (f *sharedFile) closeStream() error {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	if f.stream == nil {
+		return nil
+	}
+	err := f.stream.Close()
+	f.stream = nil
+	return err
+}
+
+// OpenLogFile 打开日志文件 path，扩展名为 RegisterCodec 注册的压缩格式时返回解压后的内容，可以传给 NewParser 逐条读取
+func OpenLogFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	c := codecFor(path)
+	if c == nil {
+		return f, nil
+	}
+	r, err := c.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return codecReader{ReadCloser: r, file: f}, nil
+}
+
+// codecReader 在关闭解压器的同时关闭文件
+type codecReader struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r codecReader) Close() error {
+	err := r.ReadCloser.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
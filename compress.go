@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SetCompress 设置默认实例是否压缩历史日志文件
+func SetCompress(compress bool) {
+	std.SetCompress(compress)
+}
+
+// SetCompress 设置是否在轮转时将之前的日志文件压缩为 .log.gz，使用 SetCodec 设置其他压缩格式
+func (l *Logger) SetCompress(compress bool) {
+	l.mu.Lock()
+	l.compress = compress
+	l.mu.Unlock()
+	if compress {
+		l.goBackground(l.compressBackups)
+	}
+}
+
+// compressBackups 压缩当前轮转周期之前的日志文件，与轮转和清理互斥
+func (l *Logger) compressBackups() {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.mu.Lock()
+	dirPath, period, compress := l.dirPath, l.period, l.compress
+	re := l.fileRegexp()
+	l.mu.Unlock()
+	if !compress {
+		return
+	}
+	codec := l.getCodec()
+	for _, dirPath := range l.logDirs(dirPath) {
+		dir := dirPath
+		if dir == "" {
+			dir = "."
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || codecFor(entry.Name()) != nil {
+				continue
+			}
+			f, ok := matchLogFile(re, entry.Name())
+			if !ok || f.date == "" || f.date >= period {
+				continue
+			}
+			name := filepath.Join(dirPath, entry.Name())
+			if compressFile(name, codec, l.getFileMode()) == nil {
+				l.notifyCompress(name, name+codec.Extension())
+			}
+		}
+	}
+}
+
+// compressFile 将文件压缩为同名加上 codec 扩展名的文件，例如 .log.gz，成功后删除原文件
+func compressFile(name string, codec Codec, mode os.FileMode) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	target := name + codec.Extension()
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	zw, err := codec.NewWriter(dst)
+	if err == nil {
+		if _, err = io.Copy(zw, src); err == nil {
+			err = zw.Close()
+		}
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err = os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,377 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configKeys 是配置支持的所有项，环境变量为 LOGGER_ 加上大写的配置项，例如 LOGGER_MAX_FILE_SIZE
+var configKeys = []string{
+	// preset 最先应用，其他配置项可以覆盖预设的配置
+	"preset",
+	"dir_mode",
+	"file_mode",
+	"encryption_key",
+	"dir",
+	"app_name",
+	"level",
+	"format",
+	"time_format",
+	"timezone",
+	"file_pattern",
+	"combined",
+	"rotation",
+	"rotation_interval",
+	"max_file_size",
+	"max_backups",
+	"max_age",
+	"compress",
+	"max_message_size",
+	"escape_newlines",
+	"file_lock",
+	"sync_policy",
+	"writers",
+	// startup_marker 最后应用，标记使用配置后的目录和格式
+	"startup_marker",
+}
+
+// ParseLevel 将 trace、debug、info、warning（warn）、error 及 RegisterLevel 注册的级别名称解析为日志级别，不区分大小写
+func ParseLevel(s string) (Level, error) {
+	name := strings.TrimSpace(s)
+	if strings.EqualFold(name, "warn") {
+		return LevelWarning, nil
+	}
+	if level, ok := lookupLevel(name); ok {
+		return level, nil
+	}
+	return LevelDebug, fmt.Errorf("未知的日志级别：%q", s)
+}
+
+// ParseFormat 将 text、json、logfmt 解析为输出格式，不区分大小写
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "logfmt":
+		return FormatLogfmt, nil
+	}
+	return FormatText, fmt.Errorf("未知的输出格式：%q", s)
+}
+
+// ConfigureFromEnv 使用环境变量配置默认实例
+func ConfigureFromEnv() error {
+	return std.ConfigureFromEnv()
+}
+
+// ConfigureFromEnv 读取 LOGGER_DIR、LOGGER_LEVEL、LOGGER_FORMAT 等环境变量进行配置，未设置的环境变量不做修改，
+// 默认实例在包初始化时已经调用过一次，LOGGER_LEVEL 可以写为 info,db=debug 同时设置模块的级别
+func (l *Logger) ConfigureFromEnv() error {
+	values := make(map[string]string)
+	for _, key := range configKeys {
+		if v, ok := os.LookupEnv("LOGGER_" + strings.ToUpper(key)); ok {
+			values[key] = v
+		}
+	}
+	return l.configure(values)
+}
+
+// ConfigureFromFile 使用配置文件配置默认实例
+func ConfigureFromFile(path string) error {
+	return std.ConfigureFromFile(path)
+}
+
+// ConfigureFromFile 读取 JSON 或 YAML 配置文件进行配置，按扩展名 .json、.yaml、.yml 区分，
+// YAML 只支持 key: value 形式的单层配置，writers 可以写为 [stdout, stderr]
+func (l *Logger) ConfigureFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		values, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		values, err = parseYAMLConfig(data)
+	default:
+		err = fmt.Errorf("不支持的配置文件格式：%s", path)
+	}
+	if err != nil {
+		return err
+	}
+	return l.configure(values)
+}
+
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch val := v.(type) {
+		case []interface{}:
+			items := make([]string, len(val))
+			for i, item := range val {
+				items[i] = fmt.Sprint(item)
+			}
+			values[k] = strings.Join(items, ",")
+		case float64:
+			values[k] = strconv.FormatFloat(val, 'f', -1, 64)
+		default:
+			values[k] = fmt.Sprint(val)
+		}
+	}
+	return values, nil
+}
+
+// listConfigKeys 是值为逗号分隔列表的配置项，YAML 中可以写为 [a, b]，其他配置项的值原样保留
+var listConfigKeys = map[string]bool{
+	"level":   true,
+	"writers": true,
+}
+
+func parseYAMLConfig(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("配置文件第 %d 行格式错误：%s", n, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !listConfigKeys[key] {
+			values[key] = yamlScalar(value)
+			continue
+		}
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		items := strings.Split(value, ",")
+		for i, item := range items {
+			items[i] = yamlScalar(strings.TrimSpace(item))
+		}
+		values[key] = strings.Join(items, ",")
+	}
+	return values, scanner.Err()
+}
+
+// yamlScalar 返回 YAML 标量的值，带引号时取引号中的内容，否则去掉行尾的注释
+func yamlScalar(value string) string {
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if i := strings.IndexByte(value[1:], value[0]); i >= 0 {
+			return value[1 : i+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// configure 应用配置，values 的 key 为 configKeys 中的配置项
+func (l *Logger) configure(values map[string]string) error {
+	for key := range values {
+		if !isConfigKey(key) {
+			return fmt.Errorf("未知的配置项：%s", key)
+		}
+	}
+	for _, key := range configKeys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if err := l.applyConfig(key, value); err != nil {
+			return fmt.Errorf("配置项 %s 错误：%w", key, err)
+		}
+	}
+	return nil
+}
+
+func isConfigKey(key string) bool {
+	i := sort.SearchStrings(sortedConfigKeys, key)
+	return i < len(sortedConfigKeys) && sortedConfigKeys[i] == key
+}
+
+var sortedConfigKeys = func() []string {
+	keys := append([]string(nil), configKeys...)
+	sort.Strings(keys)
+	return keys
+}()
+
+func (l *Logger) applyConfig(key, value string) error {
+	switch key {
+	case "preset":
+		return l.applyPreset(value)
+	case "dir_mode", "file_mode":
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil {
+			return err
+		}
+		if key == "dir_mode" {
+			l.SetDirMode(os.FileMode(mode))
+		} else {
+			l.SetFileMode(os.FileMode(mode))
+		}
+	case "encryption_key":
+		key, err := hex.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		return l.SetEncryptionKey(key)
+	case "dir":
+		return l.SetDirE(value)
+	case "app_name":
+		l.SetAppName(value)
+	case "level":
+		return l.applyLevels(value)
+	case "format":
+		f, err := ParseFormat(value)
+		if err != nil {
+			return err
+		}
+		l.SetFormat(f)
+	case "time_format":
+		l.SetTimeFormat(value)
+	case "timezone":
+		return l.SetTimezone(value)
+	case "file_pattern":
+		l.SetFilePattern(value)
+	case "combined":
+		combined, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		l.SetCombinedOutput(combined)
+	case "rotation":
+		p, err := ParseRotationPolicy(value)
+		if err != nil {
+			return err
+		}
+		l.SetRotationPolicy(p)
+	case "rotation_interval":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		l.SetRotationInterval(d)
+	case "max_file_size":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		l.SetMaxFileSize(n)
+	case "max_backups":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		l.SetMaxBackups(n)
+	case "max_age":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		l.SetMaxAge(n)
+	case "compress":
+		compress, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		l.SetCompress(compress)
+	case "max_message_size":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		l.SetMaxMessageSize(n)
+	case "escape_newlines":
+		escape, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		l.SetEscapeNewlines(escape)
+	case "file_lock":
+		lock, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		l.SetFileLock(lock)
+	case "sync_policy":
+		policy, err := parseSyncPolicy(value)
+		if err != nil {
+			return err
+		}
+		l.SetSyncPolicy(policy)
+	case "writers":
+		for _, name := range strings.Split(value, ",") {
+			w, err := namedWriter(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			if w == nil {
+				continue
+			}
+			// 代码中已经添加过的 writer 不再重复添加
+			if _, err := l.AppendWriter(w); err != nil && !errors.Is(err, ErrDuplicateWriter) {
+				return err
+			}
+		}
+	case "startup_marker":
+		marker, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		if marker {
+			l.WriteStartupMarker()
+		}
+	}
+	return nil
+}
+
+// parseSyncPolicy 解析同步策略：never、every_write 或者 1s 这样的同步间隔
+func parseSyncPolicy(s string) (SyncPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "never", "":
+		return SyncNever, nil
+	case "every_write":
+		return SyncEveryWrite, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return SyncNever, fmt.Errorf("未知的同步策略：%q", s)
+	}
+	return SyncInterval(d), nil
+}
+
+// namedWriter 返回配置中的 writer，支持 stdout、stderr
+func namedWriter(name string) (io.Writer, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+	return nil, fmt.Errorf("未知的 writer：%q", name)
+}
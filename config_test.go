@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAMLConfig(t *testing.T) {
+	data := `
+# 日志配置
+---
+dir: /var/log/app # 日志目录
+time_format: "Jan 2, 2006 15:04:05"
+file_pattern: '{app}, {date}.{level}.log'
+app_name: order # 订单服务
+level: [info, db=debug]
+writers: [stdout, "stderr"]
+`
+	got, err := parseYAMLConfig([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"dir":          "/var/log/app",
+		"time_format":  "Jan 2, 2006 15:04:05",
+		"file_pattern": "{app}, {date}.{level}.log",
+		"app_name":     "order",
+		"level":        "info,db=debug",
+		"writers":      "stdout,stderr",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAMLConfig 的结果为 %q，应当为 %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// levelColors 是各级别在终端中的 ANSI 颜色
+var levelColors = map[Level]string{
+	LevelTrace:   "\x1b[90m",
+	LevelDebug:   "\x1b[36m",
+	LevelInfo:    "\x1b[32m",
+	LevelWarning: "\x1b[33m",
+	LevelError:   "\x1b[31m",
+}
+
+// EnableConsole 为默认实例开启控制台输出
+func EnableConsole(colored bool) {
+	std.EnableConsole(colored)
+}
+
+// EnableConsole 将日志同时输出到标准输出，colored 为 true 且标准输出是终端时，文本格式中的级别会带有颜色，
+// 之后通过 RegisterLevel 注册和 At 创建的级别同样会输出到控制台，与 EnableConsoleSplit 互相替换，后调用的生效
+func (l *Logger) EnableConsole(colored bool) {
+	l.setConsole(&consoleOutput{stdout: os.Stdout, stdoutColored: colored && isTerminal(os.Stdout)})
+}
+
+// setConsole 替换控制台输出的设置，并重新组合已打开的各级别的输出
+func (l *Logger) setConsole(c *consoleOutput) {
+	l.mu.Lock()
+	l.console = c
+	l.mu.Unlock()
+	l.refreshWriters()
+}
+
+// EnableConsoleSplit 为默认实例开启分流的控制台输出
+func EnableConsoleSplit(colored bool) {
+	std.EnableConsoleSplit(colored)
+}
+
+// EnableConsoleSplit 将日志同时输出到控制台，WARNING 及以上的级别输出到标准错误，其他级别输出到标准输出，
+// 便于容器编排工具区分两个输出流，colored 的含义与 EnableConsole 相同，
+// 与 EnableConsole 互相替换，后调用的生效，不会重复输出
+func (l *Logger) EnableConsoleSplit(colored bool) {
+	l.setConsole(&consoleOutput{
+		stdout:        os.Stdout,
+		stdoutColored: colored && isTerminal(os.Stdout),
+		stderr:        os.Stderr,
+		stderrColored: colored && isTerminal(os.Stderr),
+	})
+}
+
+// consoleOutput 是控制台输出的设置
+type consoleOutput struct {
+	stdout        io.Writer
+	stdoutColored bool
+	// stderr 不为 nil 时 WARNING 及以上的级别写入 stderr
+	stderr        io.Writer
+	stderrColored bool
+}
+
+// writerFor 返回 level 级别的日志写入控制台的 writer
+func (c *consoleOutput) writerFor(level Level) consoleWriter {
+	if c.stderr != nil && level.Rank() >= LevelWarning.Rank() {
+		return consoleWriter{writer: c.stderr, level: level, colored: c.stderrColored}
+	}
+	return consoleWriter{writer: c.stdout, level: level, colored: c.stdoutColored}
+}
+
+// consoleWriter 向控制台写入一个级别的日志
+type consoleWriter struct {
+	writer  io.Writer
+	level   Level
+	colored bool
+}
+
+func (w consoleWriter) Write(p []byte) (int, error) {
+	if !w.colored || levelColors[w.level] == "" || bytes.HasPrefix(p, []byte("{")) {
+		return w.writer.Write(p)
+	}
+	tag := []byte(" " + w.level.String() + " ")
+	i := bytes.Index(p, tag)
+	if i < 0 {
+		return w.writer.Write(p)
+	}
+	var b bytes.Buffer
+	b.Write(p[:i+1])
+	b.WriteString(levelColors[w.level])
+	b.WriteString(w.level.String())
+	b.WriteString("\x1b[0m")
+	b.Write(p[i+len(tag)-1:])
+	if _, err := w.writer.Write(b.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (w consoleWriter) concurrentSafe() {}
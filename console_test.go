@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer 是并发安全的 bytes.Buffer
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// registerTestLevel 注册测试使用的级别，已经注册过时返回已有的级别
+func registerTestLevel(t *testing.T, name string, rank int) Level {
+	t.Helper()
+	if level, ok := lookupLevel(name); ok {
+		return level
+	}
+	level, err := RegisterLevel(name, rank)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return level
+}
+
+func TestConsoleCoversLaterLevels(t *testing.T) {
+	var stdout syncBuffer
+	l := New(WithDir(t.TempDir()))
+	defer l.Close()
+	l.setConsole(&consoleOutput{stdout: &stdout})
+	notice := registerTestLevel(t, "NOTICE", 2)
+	l.Info.Println("info message")
+	l.At(l.AccessLevel()).Println("access message")
+	l.At(notice).Println("notice message")
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"info message", "access message", "notice message"} {
+		if got := strings.Count(stdout.String(), msg); got != 1 {
+			t.Errorf("控制台中 %q 出现了 %d 次，应当为 1 次，输出：%s", msg, got, stdout.String())
+		}
+	}
+}
+
+func TestConsoleSplitReplacesConsole(t *testing.T) {
+	var stdout, stderr syncBuffer
+	l := New(WithDir(t.TempDir()))
+	defer l.Close()
+	l.setConsole(&consoleOutput{stdout: &stdout})
+	l.setConsole(&consoleOutput{stdout: &stdout, stderr: &stderr})
+	notice := registerTestLevel(t, "NOTICE", 2)
+	l.Info.Println("info message")
+	l.At(notice).Println("notice message")
+	l.Warning.Println("warning message")
+	l.Error.Println("error message")
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		out  *syncBuffer
+		name string
+		want []string
+		not  []string
+	}{
+		{&stdout, "标准输出", []string{"info message", "notice message"}, []string{"warning message", "error message"}},
+		{&stderr, "标准错误", []string{"warning message", "error message"}, []string{"info message", "notice message"}},
+	} {
+		for _, msg := range tt.want {
+			if got := strings.Count(tt.out.String(), msg); got != 1 {
+				t.Errorf("%s中 %q 出现了 %d 次，应当为 1 次，输出：%s", tt.name, msg, got, tt.out.String())
+			}
+		}
+		for _, msg := range tt.not {
+			if strings.Contains(tt.out.String(), msg) {
+				t.Errorf("%s中不应当出现 %q，输出：%s", tt.name, msg, tt.out.String())
+			}
+		}
+	}
+}
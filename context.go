@@ -0,0 +1,65 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext 返回携带键值对的 context，使用 WithContext 输出日志时会自动附加这些键值对，
+// 参数按 key1, value1, key2, value2... 的顺序传入，会追加在 ctx 中已有的键值对之后
+func NewContext(ctx context.Context, kv ...interface{}) context.Context {
+	fields := (&fieldLogger{fields: contextFields(ctx)}).With(kv...).fields
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// contextFields 返回 NewContext 存入 ctx 的键值对
+func contextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextKey{}).([]Field)
+	return fields
+}
+
+// contextLogger 是附加了键值对、context 或模块名的一组日志记录器
+type contextLogger struct {
+	Trace   *fieldLogger
+	Debug   *fieldLogger
+	Info    *fieldLogger
+	Warning *fieldLogger
+	Error   *fieldLogger
+}
+
+// WithContext 返回默认实例附加了 ctx 中键值对的一组日志记录器
+func WithContext(ctx context.Context) *contextLogger {
+	return std.WithContext(ctx)
+}
+
+// WithContext 返回附加了 ctx 中键值对的一组日志记录器
+func (l *Logger) WithContext(ctx context.Context) *contextLogger {
+	return &contextLogger{
+		Trace:   l.Trace.WithContext(ctx),
+		Debug:   l.Debug.WithContext(ctx),
+		Info:    l.Info.WithContext(ctx),
+		Warning: l.Warning.WithContext(ctx),
+		Error:   l.Error.WithContext(ctx),
+	}
+}
+
+// WithContext 附加 ctx 中由 NewContext 存入的键值对，ctx 本身会作为 Entry.Context 传给 Hook
+func (l *logger) WithContext(ctx context.Context) *fieldLogger {
+	return (&fieldLogger{logger: l}).WithContext(ctx)
+}
+
+func (l *errorLogger) WithContext(ctx context.Context) *fieldLogger {
+	return (&fieldLogger{logger: &l.logger, expandErr: true}).WithContext(ctx)
+}
+
+func (l *fieldLogger) WithContext(ctx context.Context) *fieldLogger {
+	fields := l.fields
+	if extra := contextFields(ctx); len(extra) > 0 {
+		fields = make([]Field, 0, len(l.fields)+len(extra))
+		fields = append(fields, l.fields...)
+		fields = append(fields, extra...)
+	}
+	return &fieldLogger{logger: l.logger, fields: fields, expandErr: l.expandErr, ctx: ctx, module: l.module, shard: l.shard}
+}
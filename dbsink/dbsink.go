@@ -0,0 +1,287 @@
+// Package dbsink 将 go_logger 中 ERROR 及以上级别的日志批量写入数据库的表，便于在管理后台中查看错误。
+//
+// 本包只依赖 database/sql，驱动由使用方引入，表需要事先创建，Schema 返回建表语句：
+//
+//	s, err := dbsink.New(db, dbsink.WithDialect(dbsink.Postgres), dbsink.WithTable("app_errors"))
+//	if err != nil {
+//		return err
+//	}
+//	s.Attach(logger.Default())
+//	defer s.Close()
+package dbsink
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+	"github.com/nickham-su/go_logger/internal/batcher"
+)
+
+// Dialect 是数据库的 SQL 方言，决定占位符和建表语句的写法
+type Dialect int
+
+const (
+	// MySQL 使用 ? 作为占位符，MySQL、MariaDB、SQLite 都可以使用
+	MySQL Dialect = iota
+	// Postgres 使用 $1、$2 作为占位符
+	Postgres
+)
+
+// columns 是写入的列，顺序与 row 的字段相同
+var columns = []string{"ts", "level", "module", "message", "caller", "error", "fields", "stack"}
+
+// tableNameRegexp 限制表名只能包含字母、数字、下划线，可以带有 schema 前缀
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Option 用于 New 的配置项
+type Option func(*Sink)
+
+// WithDialect 设置 SQL 方言，默认为 MySQL
+func WithDialect(d Dialect) Option {
+	return func(s *Sink) {
+		s.dialect = d
+	}
+}
+
+// WithTable 设置写入的表名，默认为 log_errors
+func WithTable(name string) Option {
+	return func(s *Sink) {
+		s.table = name
+	}
+}
+
+// WithMinLevel 设置写入数据库的最低级别，默认为 ERROR
+func WithMinLevel(level logger.Level) Option {
+	return func(s *Sink) {
+		s.minRank = level.Rank()
+	}
+}
+
+// WithBatchSize 设置每次写入的最大条数，默认为 50
+func WithBatchSize(n int) Option {
+	return func(s *Sink) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval 设置未凑满一批时的最长等待时间，默认为 1 秒
+func WithFlushInterval(d time.Duration) Option {
+	return func(s *Sink) {
+		if d > 0 {
+			s.flushInterval = d
+		}
+	}
+}
+
+// WithTimeout 设置每批写入的超时时间，默认为 5 秒
+func WithTimeout(d time.Duration) Option {
+	return func(s *Sink) {
+		if d > 0 {
+			s.timeout = d
+		}
+	}
+}
+
+// WithErrorHandler 设置写入失败时的回调，默认忽略错误
+func WithErrorHandler(h func(err error)) Option {
+	return func(s *Sink) {
+		s.onError = h
+	}
+}
+
+// Sink 收集日志并按批写入数据库，无法开始或提交事务时这一批保留在队列开头，在下一个 flushInterval 重试，
+// 插入某一行失败时（例如内容超出列的长度）重试也不会成功，这一批被丢弃，等待写入的日志超过 10000 条时丢弃新的日志
+type Sink struct {
+	db            *sql.DB
+	dialect       Dialect
+	table         string
+	minRank       int
+	batchSize     int
+	flushInterval time.Duration
+	timeout       time.Duration
+	onError       func(err error)
+	batch         *batcher.Batcher[row]
+
+	// stmtMu 保护 stmt，Close 之后 Flush 直接写入时与 Close 互斥
+	stmtMu sync.Mutex
+	stmt   *sql.Stmt
+}
+
+// row 是等待写入的一行
+type row struct {
+	ts      time.Time
+	level   string
+	module  string
+	message string
+	caller  string
+	err     string
+	fields  string
+	stack   string
+}
+
+// New 创建写入 db 的 Sink 并预编译插入语句，表名无效或预编译失败时返回错误
+func New(db *sql.DB, opts ...Option) (*Sink, error) {
+	s := &Sink{
+		db:            db,
+		table:         "log_errors",
+		minRank:       logger.LevelError.Rank(),
+		batchSize:     50,
+		flushInterval: time.Second,
+		timeout:       5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !tableNameRegexp.MatchString(s.table) {
+		return nil, fmt.Errorf("无效的表名：%q", s.table)
+	}
+	stmt, err := db.Prepare(s.insertSQL())
+	if err != nil {
+		return nil, err
+	}
+	s.stmt = stmt
+	s.batch = batcher.New(s.batchSize, s.flushInterval, s.insertTx, s.onError)
+	return s, nil
+}
+
+// Schema 返回创建日志表的 SQL 语句
+func (s *Sink) Schema() string {
+	if s.dialect == Postgres {
+		return "CREATE TABLE IF NOT EXISTS " + s.table + ` (
+	id BIGSERIAL PRIMARY KEY,
+	ts TIMESTAMPTZ NOT NULL,
+	level VARCHAR(16) NOT NULL,
+	module VARCHAR(64) NOT NULL,
+	message TEXT NOT NULL,
+	caller VARCHAR(255) NOT NULL,
+	error TEXT NOT NULL,
+	fields TEXT NOT NULL,
+	stack TEXT NOT NULL
+)`
+	}
+	return "CREATE TABLE IF NOT EXISTS " + s.table + ` (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	ts DATETIME(6) NOT NULL,
+	level VARCHAR(16) NOT NULL,
+	module VARCHAR(64) NOT NULL,
+	message TEXT NOT NULL,
+	caller VARCHAR(255) NOT NULL,
+	error TEXT NOT NULL,
+	fields TEXT NOT NULL,
+	stack TEXT NOT NULL
+)`
+}
+
+// insertSQL 返回插入一行的语句
+func (s *Sink) insertSQL() string {
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		if s.dialect == Postgres {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		} else {
+			placeholders[i] = "?"
+		}
+	}
+	return "INSERT INTO " + s.table + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// Attach 为 l 添加将日志写入数据库的 Hook
+func (s *Sink) Attach(l *logger.Logger) {
+	l.AddHook(s)
+}
+
+// Fire 实现 logger.Hook，将达到最低级别的日志加入待写入的批次
+func (s *Sink) Fire(e *logger.Entry) {
+	if e.Level.Rank() < s.minRank {
+		return
+	}
+	r := row{
+		ts:      e.Time,
+		level:   e.Level.String(),
+		module:  e.Module,
+		message: e.Message,
+		caller:  e.Caller,
+		fields:  encodeFields(e.Fields),
+		stack:   e.Stack,
+	}
+	if e.Err != nil {
+		r.err = e.Err.Error()
+	}
+	s.batch.Add(r)
+}
+
+// Flush 立即写入已收集的日志并等待写入完成
+func (s *Sink) Flush() error {
+	return s.batch.Flush()
+}
+
+// Close 停止后台写入，写入剩余的日志并关闭预编译的语句，不会关闭 db
+func (s *Sink) Close() error {
+	err := s.batch.Close()
+	s.stmtMu.Lock()
+	defer s.stmtMu.Unlock()
+	if closeErr := s.stmt.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// insertTx 在一个事务中写入一批日志，插入失败时标记为 batcher.Permanent
+func (s *Sink) insertTx(batch []row) error {
+	s.stmtMu.Lock()
+	defer s.stmtMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt := tx.StmtContext(ctx, s.stmt)
+	for _, r := range batch {
+		if _, err := stmt.ExecContext(ctx, r.ts, r.level, r.module, r.message, r.caller, r.err, r.fields, r.stack); err != nil {
+			_ = tx.Rollback()
+			return batcher.Permanent(fmt.Errorf("写入日志到 %s 失败：%w", s.table, err))
+		}
+	}
+	return tx.Commit()
+}
+
+// encodeFields 将字段编码为 JSON 对象，没有字段时为空字符串，无法编码的值使用 fmt.Sprint
+func encodeFields(fields []logger.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		var v interface{}
+		switch val := f.Value.(type) {
+		case func() string:
+			v = val()
+		case error:
+			v = val.Error()
+		case fmt.Stringer:
+			v = val.String()
+		default:
+			v = val
+		}
+		if _, err := json.Marshal(v); err != nil {
+			v = fmt.Sprint(v)
+		}
+		m[f.Key] = v
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// deduper 合并连续重复的日志：第一条正常输出，之后每个窗口期内重复的条数汇总为一条日志
+type deduper struct {
+	owner  *Logger
+	mu     sync.Mutex
+	logger *logger
+	shard  string
+	module string
+	key    string
+	// count 是最近一次汇总后被合并的条数
+	count int
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// SetDedup 设置默认实例合并重复日志的窗口期
+func SetDedup(window time.Duration) {
+	std.SetDedup(window)
+}
+
+// SetDedup 合并连续重复的日志：相同级别、相同内容、错误和字段的日志连续出现时只输出第一条，之后的条数每隔 window
+// 或出现不同的日志时汇总输出一条 "last message repeated N times"，window <= 0 时关闭合并，关闭前会输出未汇总的条数
+func (l *Logger) SetDedup(window time.Duration) {
+	var d *deduper
+	if window > 0 {
+		d = &deduper{owner: l, done: make(chan struct{})}
+		d.wg.Add(1)
+		go d.run(window)
+	}
+	if old := l.dedup.Swap(d); old != nil {
+		old.stop()
+	}
+}
+
+func (d *deduper) run(window time.Duration) {
+	defer d.wg.Done()
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.mu.Lock()
+			lv, e := d.takeLocked()
+			d.mu.Unlock()
+			emitSummary(lv, e)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// stop 停止后台 goroutine 并输出未汇总的条数
+func (d *deduper) stop() {
+	close(d.done)
+	d.wg.Wait()
+	d.reset()
+}
+
+// reset 输出未汇总的条数并清除上一条日志，之后的第一条日志不会被合并
+func (d *deduper) reset() {
+	d.mu.Lock()
+	lv, e := d.takeLocked()
+	d.key = ""
+	d.mu.Unlock()
+	emitSummary(lv, e)
+}
+
+// suppress 判断该日志是否与上一条重复，重复时只计数，不重复时先输出上一条的汇总
+func (d *deduper) suppress(lv *logger, e *Entry) bool {
+	key := dedupKey(e)
+	d.mu.Lock()
+	if lv == d.logger && key == d.key {
+		d.count++
+		d.mu.Unlock()
+		return true
+	}
+	prev, summary := d.takeLocked()
+	d.logger = lv
+	d.shard = e.shard
+	d.module = e.Module
+	d.key = key
+	d.mu.Unlock()
+	// 在返回之前输出汇总，保证汇总在这条不同的日志之前
+	emitSummary(prev, summary)
+	return false
+}
+
+// dedupKey 返回判断重复时比较的内容，包括日志内容、错误和字段，结构化格式下 error 不在 Message 中
+func dedupKey(e *Entry) string {
+	var b bytes.Buffer
+	b.WriteString(e.shard)
+	b.WriteByte(0)
+	b.WriteString(e.Module)
+	b.WriteByte(0)
+	b.WriteString(e.Message)
+	b.WriteByte(0)
+	if e.Err != nil {
+		b.WriteString(e.Err.Error())
+	}
+	b.WriteByte(0)
+	writeJSONFields(&b, e.Fields)
+	return b.String()
+}
+
+// takeLocked 取出被合并的条数对应的汇总日志，没有时返回 nil，调用方需持有 mu，
+// 并在释放 mu 之后调用 emitSummary 输出，避免 Hook 或 writer 通过同一个 Logger 输出日志时死锁
+func (d *deduper) takeLocked() (*logger, *Entry) {
+	if d.count == 0 {
+		return nil, nil
+	}
+	e := &Entry{
+		Time:    d.owner.now(),
+		Level:   d.logger.level,
+		Message: fmt.Sprintf("last message repeated %d times", d.count),
+		Module:  d.module,
+		shard:   d.shard,
+	}
+	d.count = 0
+	return d.logger, e
+}
+
+// emitSummary 输出 takeLocked 取出的汇总日志
+func emitSummary(lv *logger, e *Entry) {
+	if e != nil {
+		lv.emit(e)
+	}
+}
+
+// deduplicated 判断该日志是否因与上一条重复而被合并
+func (l *Logger) deduplicated(lv *logger, e *Entry) bool {
+	d := l.dedup.Load()
+	return d != nil && d.suppress(lv, e)
+}
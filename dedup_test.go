@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDedupDistinguishesErrorsAndFields(t *testing.T) {
+	l := New(WithDir(t.TempDir()), WithFormat(FormatJSON))
+	l.DisableFileOutput()
+	r := &entryRecorder{}
+	l.AddHook(r)
+	l.SetDedup(time.Hour)
+	l.Error.Println("db failed", errors.New("timeout"))
+	l.Error.Println("db failed", errors.New("refused"))
+	l.Info.With("id", 1).Println("done")
+	l.Info.With("id", 2).Println("done")
+	l.Named("worker").Info.Println("tick")
+	l.Named("worker").Info.Println("tick")
+	_ = l.Close()
+	entries := r.list()
+	if len(entries) != 6 {
+		t.Fatalf("应当输出 6 条日志，得到 %d 条：%+v", len(entries), entries)
+	}
+	if entries[1].Err == nil || entries[1].Err.Error() != "refused" {
+		t.Errorf("错误不同的日志不应当被合并，第二条的错误为 %v", entries[1].Err)
+	}
+	summary := entries[5]
+	if summary.Message != "last message repeated 1 times" || summary.Module != "worker" {
+		t.Errorf("汇总应当带有模块名，得到 %q module=%q", summary.Message, summary.Module)
+	}
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"time"
+)
+
+// diskCheckInterval 是检查磁盘剩余空间的间隔
+const diskCheckInterval = 10 * time.Second
+
+// SetMinFreeDiskSpace 设置默认实例日志目录所在磁盘的最小剩余空间
+func SetMinFreeDiskSpace(bytes int64) {
+	std.SetMinFreeDiskSpace(bytes)
+}
+
+// SetMinFreeDiskSpace 设置日志目录所在磁盘的最小剩余空间，后台每 10 秒检查一次，
+// 剩余空间不足时 WARNING 以下的级别不再写入日志文件（附加的 writer 不受影响），并输出一条 WARNING 日志，
+// 空间恢复后自动恢复写入，bytes <= 0 时关闭检查，不支持获取剩余空间的平台上不做检查
+func (l *Logger) SetMinFreeDiskSpace(bytes int64) {
+	l.minFreeDisk.Store(bytes)
+	l.diskGuardOnce.Do(func() {
+		l.goBackground(func() {
+			ticker := time.NewTicker(diskCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-l.stop:
+					return
+				case <-ticker.C:
+					l.checkDiskSpace()
+				}
+			}
+		})
+	})
+	l.checkDiskSpace()
+}
+
+// checkDiskSpace 检查剩余空间，状态变化时切换低级别日志的文件输出
+func (l *Logger) checkDiskSpace() {
+	min := l.minFreeDisk.Load()
+	l.mu.Lock()
+	dir := l.dirPath
+	l.mu.Unlock()
+	if dir == "" {
+		dir = "."
+	}
+	free, ok := freeDiskSpace(dir)
+	low := min > 0 && ok && free < min
+	if l.diskLow.Swap(low) == low {
+		return
+	}
+	l.refreshWriters()
+	if low {
+		l.Warning.Printf("日志目录 %s 所在磁盘剩余空间 %d 字节，低于 %d 字节，暂停将 WARNING 以下级别的日志写入文件", dir, free, min)
+	} else {
+		l.Warning.Printf("日志目录 %s 所在磁盘剩余空间已恢复，恢复写入所有级别的日志文件", dir)
+	}
+}
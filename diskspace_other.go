@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+
+package logger
+
+// freeDiskSpace 在不支持的平台上无法获取剩余空间
+func freeDiskSpace(dir string) (int64, bool) {
+	return 0, false
+}
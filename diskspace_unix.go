@@ -0,0 +1,14 @@
+//go:build linux || darwin || freebsd
+
+package logger
+
+import "syscall"
+
+// freeDiskSpace 返回 dir 所在文件系统中非特权用户可用的字节数
+func freeDiskSpace(dir string) (int64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	return int64(st.Bavail) * int64(st.Bsize), true
+}
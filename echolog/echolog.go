@@ -0,0 +1,85 @@
+// Package echolog 将 echo 的访问日志、错误和 panic 写入 go_logger：
+//
+//	e := echo.New()
+//	e.Use(echolog.Middleware(logger.Default()), echolog.Recover(logger.Default()))
+//	e.HTTPErrorHandler = echolog.ErrorHandler(logger.Default(), e.DefaultHTTPErrorHandler)
+//
+// 本包是独立的 module，只有使用时才会引入 echo 的依赖。
+package echolog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nickham-su/go_logger"
+)
+
+// Middleware 返回记录访问日志的中间件，每个请求完成后以 l.AccessLevel() 输出一条日志，
+// 包含 method、path（路由模板）、status、latency、bytes 和 ip
+func Middleware(l *logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// 由 HTTPErrorHandler 写入响应，以便记录最终的状态码
+				c.Error(err)
+			}
+			req, res := c.Request(), c.Response()
+			path := c.Path()
+			if path == "" {
+				path = req.URL.Path
+			}
+			l.At(l.AccessLevel()).With(
+				"method", req.Method,
+				"path", path,
+				"status", res.Status,
+				"latency", time.Since(start),
+				"bytes", res.Size,
+				"ip", c.RealIP(),
+			).Println(req.Method, req.URL.Path)
+			return nil
+		}
+	}
+}
+
+// Recover 返回捕获 panic 的中间件，panic 的值和调用栈以 ERROR 级别输出，并返回 500
+func Recover(l *logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			panicked := true
+			defer func() {
+				if panicked {
+					err = echo.NewHTTPError(http.StatusInternalServerError)
+				}
+			}()
+			defer l.RecoverAndLog(false)
+			err = next(c)
+			panicked = false
+			return err
+		}
+	}
+}
+
+// ErrorHandler 返回先记录错误再交给 next 写入响应的 echo.HTTPErrorHandler，
+// 状态码为 5xx 的错误以 ERROR 级别输出，其余以 WARNING 级别输出
+func ErrorHandler(l *logger.Logger, next echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		status := http.StatusInternalServerError
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			status = he.Code
+		}
+		req := c.Request()
+		msg := fmt.Sprint(req.Method, " ", req.URL.Path, ":")
+		if status >= http.StatusInternalServerError {
+			l.Error.With("status", status).Println(msg, err)
+		} else {
+			l.Warning.With("status", status).Println(msg, err)
+		}
+		next(err, c)
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import "bytes"
+
+// Encoder 将一条日志编码后追加到 buf，每条日志应编码为一个完整的帧，例如以换行结尾的一行或一个 msgpack 对象
+type Encoder interface {
+	Encode(buf *bytes.Buffer, e *Entry)
+}
+
+// 内置的编码器，由 SetEncoder 使用时会遵循 SetTimeFormat 等配置
+var (
+	TextEncoder    Encoder = builtinEncoder{FormatText}
+	JSONEncoder    Encoder = builtinEncoder{FormatJSON}
+	LogfmtEncoder  Encoder = builtinEncoder{FormatLogfmt}
+	MsgpackEncoder Encoder = builtinEncoder{formatMsgpack}
+)
+
+// builtinEncoder 是按内置格式编码的 Encoder
+type builtinEncoder struct {
+	format Format
+}
+
+func (enc builtinEncoder) Encode(buf *bytes.Buffer, e *Entry) {
+	encodeEntry(buf, e, encodeOptions{format: enc.format})
+}
+
+// SetEncoder 设置默认实例的编码器
+func SetEncoder(enc Encoder) {
+	std.SetEncoder(enc)
+}
+
+// SetEncoder 设置编码器，优先于 SetFormat，为 nil 时恢复使用 SetFormat 设置的格式，
+// 自定义的编码器收到的 Entry.Fields 已包含 SetAppName 等设置的进程标识字段
+func (l *Logger) SetEncoder(enc Encoder) {
+	if enc == nil {
+		l.encoder.Store(nil)
+		return
+	}
+	l.encoder.Store(&enc)
+}
+
+// encodeCustom 使用自定义的编码器编码 e
+func encodeCustom(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	if len(opts.identity) > 0 {
+		entry := *e
+		entry.Fields = make([]Field, 0, len(opts.identity)+len(e.Fields))
+		entry.Fields = append(entry.Fields, opts.identity...)
+		entry.Fields = append(entry.Fields, e.Fields...)
+		e = &entry
+	}
+	opts.encoder.Encode(b, e)
+}
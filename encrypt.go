@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// recordMagic 是每条加密记录的开头，日志文本中不会出现 NUL，因此可以与加密之前写入的明文行区分
+var recordMagic = []byte("\x00GLE")
+
+// maxRecordSize 是一条加密记录的最大字节数，用于识别损坏的文件
+const maxRecordSize = 1 << 30
+
+// ErrDecrypt 是密钥错误或加密记录被篡改时解密返回的错误
+var ErrDecrypt = errors.New("解密日志失败：密钥错误或文件已损坏")
+
+// SetEncryptionKey 为默认实例设置日志文件的加密密钥
+func SetEncryptionKey(key []byte) error {
+	return std.SetEncryptionKey(key)
+}
+
+// SetEncryptionKey 设置日志文件的加密密钥，key 为 16、24 或 32 字节时分别使用 AES-128、AES-192、AES-256 的 GCM 模式，
+// 之后写入日志文件的每条日志（开启 SetBuffered 时为每次写入的缓冲区）加密为一条记录，轮转、切分和压缩后的文件同样是加密的，
+// 附加的 writer 和审计日志不加密，使用 DecryptFile、NewDecryptReader 或 cmd/go_logger-decrypt 解密，key 为 nil 时不再加密，
+// 配置项 encryption_key（环境变量 LOGGER_ENCRYPTION_KEY）为十六进制编码的密钥
+func (l *Logger) SetEncryptionKey(key []byte) error {
+	if key == nil {
+		l.encryption.Store(nil)
+		return nil
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	l.encryption.Store(&aead)
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("加密密钥错误：%w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptRecord 将 p 加密为一条记录：4 字节的 recordMagic、4 字节大端序的密文长度、随机的 nonce 和密文
+func encryptRecord(aead cipher.AEAD, p []byte) ([]byte, error) {
+	size := aead.NonceSize() + len(p) + aead.Overhead()
+	record := make([]byte, 0, len(recordMagic)+4+size)
+	record = append(record, recordMagic...)
+	record = binary.BigEndian.AppendUint32(record, uint32(size))
+	nonce := record[len(record) : len(record)+aead.NonceSize()]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	record = record[:len(record)+len(nonce)]
+	return aead.Seal(record, nonce, p, nil), nil
+}
+
+// writeFile 将 p 写入当前文件，设置了加密密钥时写入加密后的记录，流式压缩时写入压缩器，调用方需持有 f.mu
+func (f *fileSink) writeFile(p []byte) (int, error) {
+	data := p
+	if aead := f.owner.encryption.Load(); aead != nil {
+		record, err := encryptRecord(*aead, p)
+		if err != nil {
+			return 0, err
+		}
+		data = record
+	}
+	var err error
+	if f.codec != nil {
+		_, err = f.file.writeStream(data)
+	} else {
+		_, err = f.file.write(data, f.owner.fileLock.Load())
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// NewDecryptReader 返回读取 r 中解密后的日志的 io.Reader，r 可以是 gzip 压缩后的文件，
+// 加密之前写入的明文行原样返回，密钥错误或记录被篡改时返回 ErrDecrypt
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptReader(r, aead)
+}
+
+func newDecryptReader(r io.Reader, aead cipher.AEAD) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(gz)
+	}
+	return &decryptReader{r: br, aead: aead}, nil
+}
+
+// DecryptFile 将加密的日志文件 path 解密后写入 w，扩展名为 RegisterCodec 注册的压缩格式时先解压
+func DecryptFile(path string, key []byte, w io.Writer) error {
+	f, err := OpenLogFile(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := NewDecryptReader(f, key)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// decryptReader 逐条解密 SetEncryptionKey 写入的记录
+type decryptReader struct {
+	r    *bufio.Reader
+	aead cipher.AEAD
+	// buf 是已解密但尚未读取的内容
+	buf []byte
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// next 读取下一条记录或明文行
+func (d *decryptReader) next() error {
+	magic, err := d.r.Peek(len(recordMagic))
+	if !bytes.Equal(magic, recordMagic) {
+		if len(magic) == 0 {
+			return err
+		}
+		line, err := d.r.ReadBytes('\n')
+		d.buf = line
+		if len(line) > 0 {
+			return nil
+		}
+		return err
+	}
+	var header [8]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return unexpectedEOF(err)
+	}
+	size := binary.BigEndian.Uint32(header[4:])
+	if size < uint32(d.aead.NonceSize()+d.aead.Overhead()) || size > maxRecordSize {
+		return ErrDecrypt
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(d.r, record); err != nil {
+		return unexpectedEOF(err)
+	}
+	nonce, ciphertext := record[:d.aead.NonceSize()], record[d.aead.NonceSize():]
+	plaintext, err := d.aead.Open(ciphertext[:0], nonce, ciphertext, nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+	d.buf = plaintext
+	return nil
+}
+
+// unexpectedEOF 将记录中途结束时的 io.EOF 转换为 io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
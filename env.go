@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// newDefault 创建默认实例并应用 LOGGER_ 开头的环境变量，
+// 使不修改代码的程序也能通过 LOGGER_LEVEL、LOGGER_DIR、LOGGER_FORMAT 等调整日志，
+// 代码中之后的 SetLevel 等调用会覆盖环境变量的配置，环境变量有误时输出到标准错误并忽略
+func newDefault() *Logger {
+	l := New()
+	if err := l.ConfigureFromEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, "go_logger：环境变量配置错误：", err)
+	}
+	return l
+}
+
+// applyLevels 应用 info,db=debug,http=warning 形式的级别配置，
+// 不带模块名的一项设置最低输出级别，其他项为 SetModuleLevel 设置的模块级别，未设置的模块沿用最低输出级别
+func (l *Logger) applyLevels(value string) error {
+	type moduleLevel struct {
+		name  string
+		level Level
+	}
+	var modules []moduleLevel
+	var base Level
+	hasBase := false
+	for _, item := range strings.Split(value, ",") {
+		name, levelName, ok := strings.Cut(item, "=")
+		if !ok {
+			level, err := ParseLevel(item)
+			if err != nil {
+				return err
+			}
+			base, hasBase = level, true
+			continue
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, moduleLevel{name: strings.TrimSpace(name), level: level})
+	}
+	// 全部解析成功后再修改，配置有误时不会只应用一部分
+	if hasBase {
+		l.SetLevel(base)
+	}
+	for _, m := range modules {
+		l.SetModuleLevel(m.name, m.level)
+	}
+	return nil
+}
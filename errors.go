@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// expandErrorln 处理 Println 的参数：最后一个参数为 error 时展开其包装链，为 nil 或值为 nil 的 error 时直接去掉
+func expandErrorln(v []interface{}) []interface{} {
+	if len(v) == 0 {
+		return v
+	}
+	last := v[len(v)-1]
+	if isNilError(last) {
+		return v[:len(v)-1]
+	}
+	if err, ok := last.(error); ok {
+		v = append(v[:len(v)-1:len(v)-1], errorChain(err))
+	}
+	return v
+}
+
+// expandErrorf 处理 Printf 的参数：为了不打乱格式化占位符，nil 或值为 nil 的 error 以空字符串代替
+func expandErrorf(v []interface{}) []interface{} {
+	if len(v) == 0 {
+		return v
+	}
+	last := v[len(v)-1]
+	if isNilError(last) {
+		return append(v[:len(v)-1:len(v)-1], "")
+	}
+	if err, ok := last.(error); ok {
+		v = append(v[:len(v)-1:len(v)-1], errorChain(err))
+	}
+	return v
+}
+
+// popError 取出 Println 参数中最后一个 error，用于结构化输出，nil 或值为 nil 的 error 会被直接去掉
+func popError(v []interface{}) ([]interface{}, error) {
+	if len(v) == 0 {
+		return v, nil
+	}
+	last := v[len(v)-1]
+	if isNilError(last) {
+		return v[:len(v)-1], nil
+	}
+	if err, ok := last.(error); ok {
+		return v[:len(v)-1], err
+	}
+	return v, nil
+}
+
+// replaceError 将 Printf 参数中最后一个 error 替换为其文本并返回该 error，用于结构化输出
+func replaceError(v []interface{}) ([]interface{}, error) {
+	if len(v) == 0 {
+		return v, nil
+	}
+	last := v[len(v)-1]
+	if isNilError(last) {
+		return append(v[:len(v)-1:len(v)-1], ""), nil
+	}
+	if err, ok := last.(error); ok {
+		return append(v[:len(v)-1:len(v)-1], err.Error()), err
+	}
+	return v, nil
+}
+
+// isNilError 判断参数是否为 nil error，包括以 error 接口传入的 nil 和值为 nil 的指针类型 error
+func isNilError(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if _, ok := v.(error); !ok {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// errorCauses 沿 errors.Unwrap 返回被包装的错误链，不包含 err 本身
+func errorCauses(err error) []error {
+	var causes []error
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause)
+	}
+	return causes
+}
+
+// errorChain 将错误及其包装链拼接为一行文本
+func errorChain(err error) string {
+	var b strings.Builder
+	b.WriteString(err.Error())
+	for _, cause := range errorCauses(err) {
+		b.WriteString(" | cause: ")
+		b.WriteString(cause.Error())
+	}
+	return b.String()
+}
+
+// PrintErr 以 msg 输出 err，记录 errors.Unwrap 得到的每一层原因，
+// 错误链中的 error 带有 pkg/errors 风格的 StackTrace 方法时附加最内层的调用栈，err 为 nil 时不输出
+func (l *errorLogger) PrintErr(err error, msg string) {
+	l.printErr(&fieldLogger{logger: &l.logger, expandErr: true}, err, msg)
+}
+
+func (l *fieldLogger) PrintErr(err error, msg string) {
+	l.logger.printErr(l, err, msg)
+}
+
+// printErr 文本格式将错误链拼接在 msg 之后，结构化格式输出 error 字段并以 causes 字段记录每一层原因
+func (l *logger) printErr(o *fieldLogger, err error, msg string) {
+	if isNilError(err) || !l.enabledFor(o.moduleName()) {
+		return
+	}
+	if !l.owner.structured() {
+		l.output(o, msg+": "+errorChain(err), nil, errorStack(err))
+		return
+	}
+	if causes := errorCauses(err); len(causes) > 0 {
+		texts := make([]string, len(causes))
+		for i, cause := range causes {
+			texts[i] = cause.Error()
+		}
+		o = o.With("causes", texts)
+	}
+	l.output(o, msg, err, errorStack(err))
+}
+
+// errorStack 返回错误链中最内层带有 StackTrace 方法的 error 记录的调用栈，没有时返回空字符串，
+// StackTrace 需返回元素为程序计数器的切片，例如 github.com/pkg/errors 的 errors.StackTrace
+func errorStack(err error) string {
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if trace := stackTrace(err); trace != nil {
+			pcs = trace
+		}
+	}
+	if pcs == nil {
+		return ""
+	}
+	return formatStack(runtime.CallersFrames(pcs))
+}
+
+// stackTrace 通过反射调用 err 的 StackTrace 方法，避免依赖 pkg/errors
+func stackTrace(err error) []uintptr {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	t := m.Type().Out(0)
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := m.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
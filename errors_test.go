@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// nilError 是用于构造值为 nil 的 error 的指针类型
+type nilError struct{}
+
+func (*nilError) Error() string { return "nil error" }
+
+func TestTrailingNilError(t *testing.T) {
+	var typedNil *nilError
+	var nilInterface error
+	err := fmt.Errorf("query failed: %w", fmt.Errorf("connection refused"))
+	chain := errorChain(err)
+	tests := []struct {
+		name string
+		last interface{}
+		// ln、f 是 expandErrorln、expandErrorf 的结果，pop、replace 是 popError、replaceError 返回的参数
+		ln, f, pop, replace []interface{}
+		wantErr             error
+	}{
+		{
+			name: "值为 nil 的指针", last: typedNil,
+			ln: []interface{}{"msg"}, f: []interface{}{"msg", ""}, pop: []interface{}{"msg"}, replace: []interface{}{"msg", ""},
+		},
+		{
+			name: "nil 的 error 接口", last: nilInterface,
+			ln: []interface{}{"msg"}, f: []interface{}{"msg", ""}, pop: []interface{}{"msg"}, replace: []interface{}{"msg", ""},
+		},
+		{
+			name: "无类型的 nil", last: nil,
+			ln: []interface{}{"msg"}, f: []interface{}{"msg", ""}, pop: []interface{}{"msg"}, replace: []interface{}{"msg", ""},
+		},
+		{
+			name: "非 nil 的 error", last: err,
+			ln: []interface{}{"msg", chain}, f: []interface{}{"msg", chain}, pop: []interface{}{"msg"}, replace: []interface{}{"msg", err.Error()},
+			wantErr: err,
+		},
+		{
+			name: "普通参数", last: 42,
+			ln: []interface{}{"msg", 42}, f: []interface{}{"msg", 42}, pop: []interface{}{"msg", 42}, replace: []interface{}{"msg", 42},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := func() []interface{} { return []interface{}{"msg", tt.last} }
+			if got := expandErrorln(args()); !reflect.DeepEqual(got, tt.ln) {
+				t.Errorf("expandErrorln = %#v，应当为 %#v", got, tt.ln)
+			}
+			if got := expandErrorf(args()); !reflect.DeepEqual(got, tt.f) {
+				t.Errorf("expandErrorf = %#v，应当为 %#v", got, tt.f)
+			}
+			if got, gotErr := popError(args()); !reflect.DeepEqual(got, tt.pop) || gotErr != tt.wantErr {
+				t.Errorf("popError = %#v, %v，应当为 %#v, %v", got, gotErr, tt.pop, tt.wantErr)
+			}
+			if got, gotErr := replaceError(args()); !reflect.DeepEqual(got, tt.replace) || gotErr != tt.wantErr {
+				t.Errorf("replaceError = %#v, %v，应当为 %#v, %v", got, gotErr, tt.replace, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+//go:build windows
+
+package logger
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Windows 事件日志的事件类型
+const (
+	eventlogErrorType   = 0x0001
+	eventlogWarningType = 0x0002
+)
+
+var (
+	advapi32                 = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW = advapi32.NewProc("RegisterEventSourceW")
+	procReportEventW         = advapi32.NewProc("ReportEventW")
+)
+
+// EnableEventLog 为默认实例添加 Windows 事件日志输出
+func EnableEventLog(source string) error {
+	return std.EnableEventLog(source)
+}
+
+// EnableEventLog 以 source 为事件源将 WARNING 及以上级别的日志写入 Windows 事件日志，
+// WARNING 记录为警告事件，ERROR 及以上记录为错误事件，可以在事件查看器的“应用程序”日志中查看
+func (l *Logger) EnableEventLog(source string) error {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if h == 0 {
+		return err
+	}
+	for _, level := range Levels() {
+		if level.Rank() >= LevelWarning.Rank() {
+			l.AppendWriterFor(level, eventLogWriter{handle: h, level: level})
+		}
+	}
+	return nil
+}
+
+// eventLogWriter 将一个级别的日志以对应的事件类型写入 Windows 事件日志
+type eventLogWriter struct {
+	handle uintptr
+	level  Level
+}
+
+func (w eventLogWriter) Write(p []byte) (int, error) {
+	msg, err := syscall.UTF16PtrFromString(strings.ReplaceAll(strings.TrimRight(string(p), "\n"), "\x00", ""))
+	if err != nil {
+		return 0, err
+	}
+	eventType := uintptr(eventlogWarningType)
+	if w.level.Rank() >= LevelError.Rank() {
+		eventType = eventlogErrorType
+	}
+	strs := []*uint16{msg}
+	// 事件 ID 固定为 1，事件源没有注册消息文件时事件查看器仍会显示日志的原文
+	ok, _, err := procReportEventW.Call(w.handle, eventType, 0, 1, 0, 1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+	if ok == 0 {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w eventLogWriter) concurrentSafe() {}
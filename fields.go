@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Field 是附加在日志上的一个键值对
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// fieldLogger 携带键值对的日志记录器，由 With 或 Fields 创建
+type fieldLogger struct {
+	logger    *logger
+	fields    []Field
+	expandErr bool
+	// ctx 是 WithContext 传入的 context，会随日志传给 Hook
+	ctx context.Context
+	// module 是 Named 设置的模块名
+	module string
+	// shard 是 ForKey 设置的键
+	shard string
+}
+
+// With 附加键值对，参数按 key1, value1, key2, value2... 的顺序传入，缺少的值记为 nil，
+// 也可以在其中传入 String、Int 等函数创建的 Field，例如 With(logger.Err(err), "user", u)
+func (l *logger) With(kv ...interface{}) *fieldLogger {
+	return (&fieldLogger{logger: l}).With(kv...)
+}
+
+// Fields 以 map 的形式附加键值对，按 key 排序输出
+func (l *logger) Fields(fields map[string]interface{}) *fieldLogger {
+	return (&fieldLogger{logger: l}).Fields(fields)
+}
+
+func (l *fieldLogger) With(kv ...interface{}) *fieldLogger {
+	fields := make([]Field, len(l.fields), len(l.fields)+(len(kv)+1)/2)
+	copy(fields, l.fields)
+	for i := 0; i < len(kv); i += 2 {
+		if f, ok := kv[i].(Field); ok {
+			fields = append(fields, f)
+			i--
+			continue
+		}
+		f := Field{Key: fmt.Sprint(kv[i])}
+		if i+1 < len(kv) {
+			f.Value = kv[i+1]
+		}
+		fields = append(fields, f)
+	}
+	return &fieldLogger{logger: l.logger, fields: fields, expandErr: l.expandErr, ctx: l.ctx, module: l.module, shard: l.shard}
+}
+
+func (l *fieldLogger) Fields(m map[string]interface{}) *fieldLogger {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kv := make([]interface{}, 0, len(m)*2)
+	for _, k := range keys {
+		kv = append(kv, k, m[k])
+	}
+	return l.With(kv...)
+}
+
+func (l *fieldLogger) Println(v ...interface{}) {
+	l.logger.println(l, v)
+}
+
+func (l *fieldLogger) Printf(format string, v ...interface{}) {
+	l.logger.printf(l, format, v)
+}
+
+// Enabled 判断该级别的日志当前是否需要输出，Named 创建的记录器使用模块的级别
+func (l *fieldLogger) Enabled() bool {
+	return l.logger.enabledFor(l.module)
+}
+
+func (l *fieldLogger) moduleName() string {
+	if l == nil {
+		return ""
+	}
+	return l.module
+}
+
+func (l *fieldLogger) expandsErr() bool {
+	return l != nil && l.expandErr
+}
+
+// fieldValue 返回用于编码的字段值，error 和 fmt.Stringer 会转换为字符串，func() string 在此时才调用
+func fieldValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case func() string:
+		return val()
+	case error:
+		if isNilError(val) {
+			return nil
+		}
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	}
+	return v
+}
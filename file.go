@@ -0,0 +1,279 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fileSink 是一个按大小切分的日志文件，合并输出时由所有级别共享，文件在第一次写入时打开
+type fileSink struct {
+	owner *Logger
+	mu    sync.Mutex
+	file  *sharedFile
+	// baseName 是不含序号和扩展名的文件名，例如 logs/2006-01-02.info
+	baseName string
+	// ext 是文件的扩展名，例如 .log，流式压缩时包括压缩格式的扩展名，例如 .log.gz
+	ext string
+	// codec 是流式压缩的格式，为 nil 时不压缩
+	codec Codec
+	// index 是按大小切分后的文件序号，0 表示没有序号的第一个文件，-1 表示尚未打开
+	index int
+	// buf 是 SetBuffered 开启时尚未写入文件的完整日志
+	buf []byte
+}
+
+// newFileSink 创建文件 baseName+ext 的 fileSink，开启 SetStreamCompression 时在 ext 之后加上压缩格式的扩展名
+func newFileSink(owner *Logger, baseName, ext string) *fileSink {
+	f := &fileSink{
+		owner:    owner,
+		baseName: baseName,
+		ext:      ext,
+		index:    -1,
+	}
+	if c := owner.streamCodec(); c != nil {
+		f.codec = c
+		f.ext += c.Extension()
+	}
+	return f
+}
+
+func (f *fileSink) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file != nil && f.shouldSplit(len(p)) {
+		f.closeFile()
+		oldName := f.fileName()
+		f.index++
+		f.owner.notifyRotate(oldName, f.fileName())
+		f.owner.goBackground(f.owner.cleanup)
+	}
+	if f.file == nil {
+		if err := f.openFile(); err != nil {
+			return 0, err
+		}
+	}
+	syncEveryWrite := f.owner.syncEveryWrite.Load()
+	if size := int(f.owner.bufferSize.Load()); size > 0 && !syncEveryWrite {
+		// 缓冲区中只保存完整的日志，多个 fileSink 共用同一文件时不会写入半行
+		if len(f.buf)+len(p) > size {
+			if err := f.flushLocked(); err != nil {
+				return 0, err
+			}
+		}
+		if len(p) <= size {
+			f.buf = append(f.buf, p...)
+			return len(p), nil
+		}
+	}
+	if err := f.flushLocked(); err != nil {
+		return 0, err
+	}
+	n, err := f.writeFile(p)
+	if err == nil && syncEveryWrite {
+		err = f.file.Sync()
+	}
+	return n, err
+}
+
+// flushLocked 将缓冲区中的日志写入文件，调用方需持有 f.mu
+func (f *fileSink) flushLocked() error {
+	if len(f.buf) == 0 || f.file == nil {
+		return nil
+	}
+	_, err := f.writeFile(f.buf)
+	f.buf = f.buf[:0]
+	return err
+}
+
+// flush 将缓冲区以及流式压缩的压缩器中的日志写入文件
+func (f *fileSink) flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.flushLocked(); err != nil {
+		return err
+	}
+	if f.file == nil {
+		return nil
+	}
+	return f.file.flushStream()
+}
+
+// openFile 打开当前的日志文件，失败时由写入方报告错误并在下一次写入时重试，调用方需持有 f.mu
+func (f *fileSink) openFile() error {
+	if f.index < 0 {
+		f.index = f.lastIndex()
+	}
+	file, err := files.open(f.fileName(), f.owner.getFileMode())
+	if os.IsNotExist(err) {
+		// ForKey 的子目录可能在清理时因为没有文件而被删除
+		if os.MkdirAll(filepath.Dir(f.fileName()), f.owner.getDirMode()) == nil {
+			file, err = files.open(f.fileName(), f.owner.getFileMode())
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败：%w", err)
+	}
+	if f.codec != nil {
+		if err := file.startStream(f.codec); err != nil {
+			_ = files.release(file)
+			return err
+		}
+	}
+	f.file = file
+	f.writeHeader()
+	return nil
+}
+
+// closeFile 释放当前文件，没有其他 fileSink 使用时文件会被关闭，调用方需持有 f.mu
+func (f *fileSink) closeFile() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.flushLocked()
+	if releaseErr := files.release(f.file); err == nil {
+		err = releaseErr
+	}
+	f.file = nil
+	return err
+}
+
+func (f *fileSink) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closeFile()
+}
+
+func (f *fileSink) sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	if err := f.flushLocked(); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+// activeName 返回正在写入的文件名，尚未打开过时返回空字符串
+func (f *fileSink) activeName() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.index < 0 {
+		return ""
+	}
+	return f.fileName()
+}
+
+// pendingName 返回下一次写入时使用的文件名，尚未打开过时按已存在的文件确定序号
+func (f *fileSink) pendingName() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.index >= 0 {
+		return f.fileName()
+	}
+	return f.nameAt(f.lastIndex())
+}
+
+// fileName 返回当前序号对应的文件名，例如 2006-01-02.info.log、2006-01-02.info.1.log
+func (f *fileSink) fileName() string {
+	return f.nameAt(f.index)
+}
+
+// nameAt 返回序号 index 对应的文件名
+func (f *fileSink) nameAt(index int) string {
+	if index <= 0 {
+		return f.baseName + f.ext
+	}
+	return f.baseName + "." + strconv.Itoa(index) + f.ext
+}
+
+// shouldSplit 判断写入 n 字节后是否会超过文件大小限制或者轮转策略要求切换文件，调用方需持有 f.mu
+func (f *fileSink) shouldSplit(n int) bool {
+	maxSize := f.owner.maxFileSize.Load()
+	size := f.file.size.Load() + int64(len(f.buf))
+	if size <= 0 {
+		return false
+	}
+	if maxSize > 0 && size+int64(n) > maxSize {
+		return true
+	}
+	p := f.owner.getRotationPolicy()
+	return p != nil && p.ShouldRotate(f.owner.now(), size+int64(n))
+}
+
+// lastIndex 返回当天已存在的最大文件序号，用于进程重启后继续追加写入最新的文件，
+// 序号不连续（较早的文件已被清理）时同样使用最大的序号，最新的文件已被压缩时返回下一个序号，不会覆盖已有的文件
+func (f *fileSink) lastIndex() int {
+	if f.owner.maxFileSize.Load() <= 0 && f.owner.getRotationPolicy() == nil {
+		return 0
+	}
+	dir, base := filepath.Split(f.baseName)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	// 流式压缩时可以继续追加写入同一格式的压缩文件，其他情况下只能追加写入未压缩的文件
+	ext, streamExt := f.ext, ""
+	if f.codec != nil {
+		streamExt = f.codec.Extension()
+		ext = strings.TrimSuffix(ext, streamExt)
+	}
+	index, plain := -1, false
+	for _, entry := range entries {
+		name, codecExt := trimCodecExt(entry.Name())
+		compressed := codecExt != streamExt
+		if !strings.HasPrefix(name, base) || !strings.HasSuffix(name, ext) || len(name) < len(base)+len(ext) {
+			continue
+		}
+		n := 0
+		if middle := name[len(base) : len(name)-len(ext)]; middle != "" {
+			if n, err = strconv.Atoi(strings.TrimPrefix(middle, ".")); err != nil || n < 1 || middle[0] != '.' {
+				continue
+			}
+		}
+		if n > index {
+			index, plain = n, !compressed
+		} else if n == index && !compressed {
+			plain = true
+		}
+	}
+	switch {
+	case index < 0:
+		return 0
+	case !plain:
+		return index + 1
+	}
+	return index
+}
+
+// reopen 重新打开正在写入的文件，尚未打开时不做任何事
+func (f *fileSink) reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	if err := f.flushLocked(); err != nil {
+		return err
+	}
+	return f.file.reopen()
+}
+
+// SetFileLock 设置默认实例写入日志文件时是否加文件锁
+func SetFileLock(enabled bool) {
+	std.SetFileLock(enabled)
+}
+
+// SetFileLock 设置写入日志文件时是否持有文件锁（flock），用于多个进程写入同一目录的日志文件，
+// 开启后每条日志在锁内以一次追加写入完成，并按文件的实际大小切分，Windows 上只依赖追加写入
+func (l *Logger) SetFileLock(enabled bool) {
+	l.fileLock.Store(enabled)
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// files 是进程内所有日志文件的管理器，同一路径的文件只打开一次，由所有写入它的 fileSink 共享，
+// 例如合并输出的各级别或指向同一目录的多个 Logger
+var files = &fileManager{files: make(map[string]*sharedFile)}
+
+// fileManager 按绝对路径管理打开的日志文件，最后一个使用者释放后关闭文件
+type fileManager struct {
+	mu    sync.Mutex
+	files map[string]*sharedFile
+}
+
+// sharedFile 是一个被引用计数的日志文件
+type sharedFile struct {
+	// mu 保护 file 的替换，写入时持有读锁
+	mu   sync.RWMutex
+	file *os.File
+	// key 是文件的绝对路径
+	key string
+	// mode 是创建文件时使用的权限，reopen 时沿用
+	mode os.FileMode
+	// refs 是持有该文件的 fileSink 数量，由 fileManager.mu 保护
+	refs int
+	// size 是文件当前的字节数，包括所有使用者的写入
+	size atomic.Int64
+	// stream 是 SetStreamCompression 开启时的压缩器，codec 是其压缩格式，由 streamMu 保护
+	streamMu sync.Mutex
+	stream   io.WriteCloser
+	codec    Codec
+}
+
+// open 返回 path 对应的共享文件，尚未打开时以追加方式打开，文件不存在时以 mode 权限创建
+func (m *fileManager) open(path string, mode os.FileMode) (*sharedFile, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f := m.files[key]; f != nil {
+		f.refs++
+		return f, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, mode)
+	if err != nil {
+		return nil, err
+	}
+	f := &sharedFile{file: file, key: key, mode: mode, refs: 1}
+	if info, err := file.Stat(); err == nil {
+		f.size.Store(info.Size())
+	}
+	m.files[key] = f
+	return f, nil
+}
+
+// release 释放对 f 的引用，没有其他使用者时关闭文件
+func (m *fileManager) release(f *sharedFile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f.refs--
+	if f.refs > 0 {
+		return nil
+	}
+	delete(m.files, f.key)
+	streamErr := f.closeStream()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	return streamErr
+}
+
+func (f *sharedFile) Write(p []byte) (int, error) {
+	return f.write(p, false)
+}
+
+// write 以一次系统调用追加写入 p，lock 为 true 时写入期间持有文件的排他锁，
+// 并使用文件的实际大小更新 size，以便多个进程写入同一文件时按相同的大小切分
+func (f *sharedFile) write(p []byte, lock bool) (int, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if !lock {
+		n, err := f.file.Write(p)
+		f.size.Add(int64(n))
+		return n, err
+	}
+	if err := lockFile(f.file); err != nil {
+		return 0, err
+	}
+	defer unlockFile(f.file)
+	n, err := f.file.Write(p)
+	if info, statErr := f.file.Stat(); statErr == nil {
+		f.size.Store(info.Size())
+	} else {
+		f.size.Add(int64(n))
+	}
+	return n, err
+}
+
+func (f *sharedFile) Sync() error {
+	if err := f.flushStream(); err != nil {
+		return err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.file.Sync()
+}
+
+// reopen 重新打开同一路径的文件并关闭旧的文件描述符，用于外部工具移动或截断文件之后
+func (f *sharedFile) reopen() error {
+	file, err := os.OpenFile(f.key, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.mode)
+	if err != nil {
+		return err
+	}
+	// 流式压缩时在旧文件中结束当前这一段压缩数据，新文件从新的一段开始
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	var streamErr error
+	if f.stream != nil {
+		streamErr = f.stream.Close()
+	}
+	f.mu.Lock()
+	old := f.file
+	f.file = file
+	f.size.Store(0)
+	if info, err := file.Stat(); err == nil {
+		f.size.Store(info.Size())
+	}
+	f.mu.Unlock()
+	if f.stream != nil {
+		w, err := f.codec.NewWriter(sharedFileWriter{f})
+		if err != nil {
+			w = nil
+			if streamErr == nil {
+				streamErr = err
+			}
+		}
+		f.stream = w
+	}
+	if err := old.Close(); err != nil {
+		return err
+	}
+	return streamErr
+}
@@ -0,0 +1,61 @@
+package logger
+
+import "os"
+
+const (
+	// defaultFileMode 是新建日志文件的默认权限，实际权限还会受 umask 影响
+	defaultFileMode os.FileMode = 0666
+	// defaultDirMode 是新建日志目录的默认权限
+	defaultDirMode os.FileMode = os.ModePerm
+)
+
+// WithFileMode 设置新建日志文件的权限
+func WithFileMode(mode os.FileMode) Option {
+	return func(l *Logger) {
+		l.fileMode.Store(uint32(mode.Perm()))
+	}
+}
+
+// WithDirMode 设置新建日志目录的权限，需要放在 WithDir 之前
+func WithDirMode(mode os.FileMode) Option {
+	return func(l *Logger) {
+		l.dirMode.Store(uint32(mode.Perm()))
+	}
+}
+
+// SetFileMode 设置默认实例新建日志文件的权限
+func SetFileMode(mode os.FileMode) {
+	std.SetFileMode(mode)
+}
+
+// SetFileMode 设置新建日志文件、压缩文件和审计日志文件的权限，例如 0640，默认为 0666，
+// 只影响之后新建的文件，实际权限还会受进程 umask 的限制
+func (l *Logger) SetFileMode(mode os.FileMode) {
+	l.fileMode.Store(uint32(mode.Perm()))
+}
+
+// SetDirMode 设置默认实例新建日志目录的权限
+func SetDirMode(mode os.FileMode) {
+	std.SetDirMode(mode)
+}
+
+// SetDirMode 设置 SetDir 新建日志目录时使用的权限，例如 0750，默认为 0777，已存在的目录不受影响
+func (l *Logger) SetDirMode(mode os.FileMode) {
+	l.dirMode.Store(uint32(mode.Perm()))
+}
+
+// getFileMode 返回新建日志文件的权限
+func (l *Logger) getFileMode() os.FileMode {
+	if mode := l.fileMode.Load(); mode != 0 {
+		return os.FileMode(mode)
+	}
+	return defaultFileMode
+}
+
+// getDirMode 返回新建日志目录的权限
+func (l *Logger) getDirMode() os.FileMode {
+	if mode := l.dirMode.Load(); mode != 0 {
+		return os.FileMode(mode)
+	}
+	return defaultDirMode
+}
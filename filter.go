@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Filter 在日志交给 Hook 和 writer 之前被调用，可以修改日志的内容，返回 false 时丢弃该日志
+type Filter func(e *Entry) bool
+
+// 内置的脱敏规则，用于 RedactPatterns
+var (
+	// RedactEmail 匹配邮箱地址
+	RedactEmail = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// RedactToken 匹配 Bearer token 以及 token、password、secret、api_key 等键对应的值，只替换值的部分
+	RedactToken = regexp.MustCompile(`(?i)(?:bearer\s+|(?:token|password|passwd|secret|api[_-]?key)["']?\s*[:=]\s*["']?)([^\s"'&,;]+)`)
+	// RedactCardNumber 匹配 13 到 19 位、可以用空格或横线分隔的银行卡号
+	RedactCardNumber = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// redacted 是脱敏后替换敏感内容的文字
+const redacted = "[REDACTED]"
+
+// AddFilter 为默认实例添加 Filter
+func AddFilter(f Filter) {
+	std.AddFilter(f)
+}
+
+// AddFilter 添加 Filter，多个 Filter 按添加的顺序调用，任一 Filter 返回 false 时丢弃该日志
+func (l *Logger) AddFilter(f Filter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var filters []Filter
+	if old := l.filters.Load(); old != nil {
+		filters = append(filters, *old...)
+	}
+	filters = append(filters, f)
+	l.filters.Store(&filters)
+}
+
+// RedactPatterns 为默认实例添加脱敏规则
+func RedactPatterns(patterns ...*regexp.Regexp) {
+	std.RedactPatterns(patterns...)
+}
+
+// RedactPatterns 添加一个 Filter，将日志内容、error 和字符串类型的字段中匹配的部分替换为 [REDACTED]，
+// 正则中有分组时只替换分组匹配的部分，例如 RedactPatterns(RedactEmail, RedactToken, RedactCardNumber)
+func (l *Logger) RedactPatterns(patterns ...*regexp.Regexp) {
+	patterns = append([]*regexp.Regexp(nil), patterns...)
+	l.AddFilter(func(e *Entry) bool {
+		e.Message = redactAll(e.Message, patterns)
+		if e.Err != nil {
+			if msg := e.Err.Error(); redactAll(msg, patterns) != msg {
+				e.Err = errors.New(redactAll(msg, patterns))
+			}
+		}
+		for i, f := range e.Fields {
+			if s, ok := fieldValue(f.Value).(string); ok {
+				if r := redactAll(s, patterns); r != s {
+					e.Fields[i].Value = r
+				}
+			}
+		}
+		return true
+	})
+}
+
+// applyFilters 依次调用所有 Filter，返回 false 表示丢弃该日志
+func (l *Logger) applyFilters(e *Entry) bool {
+	filters := l.filters.Load()
+	if filters == nil {
+		return true
+	}
+	// 与 fireHooks 相同，复制 Fields 以免修改 With 创建的 fieldLogger 中的字段
+	e.Fields = append([]Field(nil), e.Fields...)
+	for _, f := range *filters {
+		if !f(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func redactAll(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = redact(s, re)
+	}
+	return s
+}
+
+// redact 替换 s 中 re 匹配的部分，re 有分组时只替换各分组匹配的部分
+func redact(s string, re *regexp.Regexp) string {
+	if re.NumSubexp() == 0 {
+		return re.ReplaceAllLiteralString(s, redacted)
+	}
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		for i := 2; i < len(m); i += 2 {
+			if m[i] < last {
+				continue
+			}
+			b.WriteString(s[last:m[i]])
+			b.WriteString(redacted)
+			last = m[i+1]
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+)
+
+// FingerprintKey 是 SetFingerprint 添加的字段名
+const FingerprintKey = "fingerprint"
+
+// fingerprintVariable 匹配日志内容中通常随每次调用变化的部分：引号中的字符串、UUID、十六进制串和数字
+var fingerprintVariable = regexp.MustCompile(`"[^"]*"|'[^']*'|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0x[0-9a-fA-F]+|\b[0-9a-fA-F]{16,}\b|\d+(\.\d+)?`)
+
+// SetFingerprint 设置默认实例是否为错误日志添加指纹
+func SetFingerprint(enabled bool) {
+	std.SetFingerprint(enabled)
+}
+
+// SetFingerprint 设置是否为 ERROR 及以上级别的日志添加 fingerprint 字段，
+// 指纹由日志的模板（Printf 的格式字符串，Println 时为去掉数字、引号中的内容等变化部分的日志内容）和调用方的函数名计算，
+// 同一处代码产生的同一类错误指纹相同，用于下游按指纹合并告警
+func (l *Logger) SetFingerprint(enabled bool) {
+	l.fingerprint.Store(enabled)
+}
+
+// addFingerprint 在开启 SetFingerprint 时为错误日志添加指纹字段，template 为空时使用去掉变化部分的日志内容
+func (l *Logger) addFingerprint(e *Entry, template string) {
+	if !l.fingerprint.Load() || e.Level.Rank() < LevelError.Rank() {
+		return
+	}
+	if template == "" {
+		template = fingerprintVariable.ReplaceAllString(e.Message, "?")
+	}
+	function := ""
+	if frame, ok := callerFrame(); ok {
+		function = frame.Function
+	}
+	fields := make([]Field, len(e.Fields), len(e.Fields)+1)
+	copy(fields, e.Fields)
+	e.Fields = append(fields, Field{Key: FingerprintKey, Value: fingerprint(e.Module, template, function)})
+}
+
+// fingerprint 返回 16 位十六进制的指纹
+func fingerprint(parts ...string) string {
+	h := sha1.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
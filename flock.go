@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile 获取 file 的排他锁，其他进程对同一文件加锁时会等待
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package logger
+
+import "os"
+
+// lockFile 在不支持 flock 的平台上不加锁，只依赖 O_APPEND 的追加写入
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
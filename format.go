@@ -0,0 +1,357 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Format 日志的输出格式
+type Format int32
+
+const (
+	// FormatText 纯文本格式：2006/01/02 15:04:05.000000 LEVEL msg，Named 创建的日志为 LEVEL [module] msg
+	FormatText Format = iota
+	// FormatJSON 每行一个 JSON 对象，包含 ts、level、msg、caller 字段
+	FormatJSON
+	// FormatLogfmt 每行为 ts=... level=info msg="..." 形式的键值对，键与 JSON 格式相同
+	FormatLogfmt
+	// formatMsgpack 每条日志为一个 msgpack map，键与 JSON 格式相同，通过 MsgpackEncoder 使用
+	formatMsgpack
+)
+
+// pkgPath 用于在调用栈中跳过本包的栈帧
+var pkgPath = reflect.TypeOf((*Logger)(nil)).Elem().PkgPath()
+
+// Entry 是一条待输出的日志
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	// Err 是错误日志最后一个参数中的 error，仅用于结构化输出
+	Err error
+	// Caller 是调用方的 文件:行号，不需要输出时为空
+	Caller string
+	// Module 是 Named 设置的模块名，没有时为空
+	Module string
+	Fields []Field
+	// Stack 是多行的调用栈，不需要输出时为空
+	Stack string
+	// Context 是通过 WithContext 或 slog 传入的 context，没有时为 nil，不参与编码
+	Context context.Context
+	// shard 是 ForKey 设置的键，不为空时写入该键单独的日志文件
+	shard string
+}
+
+const (
+	// defaultTextTimeFormat 与标准库 log.Ldate|log.Lmicroseconds 的格式相同
+	defaultTextTimeFormat = "2006/01/02 15:04:05.000000"
+	defaultJSONTimeFormat = time.RFC3339Nano
+)
+
+// encodeOptions 是编码日志时使用的配置
+type encodeOptions struct {
+	format Format
+	// timeFormat 为空时使用各格式默认的时间格式
+	timeFormat string
+	// identity 是附加在每条日志字段之前的进程标识
+	identity []Field
+	// encoder 是 SetEncoder 设置的自定义编码器，为 nil 时按 format 编码
+	encoder Encoder
+	// escapeNewlines 为 true 时文本格式的每条日志只占一行
+	escapeNewlines bool
+}
+
+func (l *Logger) encodeOptions() encodeOptions {
+	opts := encodeOptions{format: l.getFormat(), identity: l.identityFields(), escapeNewlines: l.escapeNewlines.Load()}
+	if enc := l.encoder.Load(); enc != nil {
+		if builtin, ok := (*enc).(builtinEncoder); ok {
+			opts.format = builtin.format
+		} else {
+			opts.encoder = *enc
+		}
+	}
+	if layout := l.timeFormat.Load(); layout != nil {
+		opts.timeFormat = *layout
+	}
+	return opts
+}
+
+// SetTimeFormat 设置默认实例的时间格式
+func SetTimeFormat(layout string) {
+	std.SetTimeFormat(layout)
+}
+
+// SetTimeFormat 设置日志中时间戳的格式，例如 time.RFC3339Nano，为空时恢复默认格式：
+// 文本格式为 2006/01/02 15:04:05.000000，JSON 和 logfmt 格式为 RFC3339Nano
+func (l *Logger) SetTimeFormat(layout string) {
+	l.timeFormat.Store(&layout)
+}
+
+// structured 判断是否为结构化的输出格式，结构化格式中 error 单独输出并总是包含调用方
+func (opts encodeOptions) structured() bool {
+	return opts.format != FormatText || opts.encoder != nil
+}
+
+// structured 判断当前的输出格式是否为结构化格式
+func (l *Logger) structured() bool {
+	return l.encodeOptions().structured()
+}
+
+// encodeEntry 将 e 编码为一条日志追加到 b
+func encodeEntry(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	switch {
+	case opts.encoder != nil:
+		encodeCustom(b, e, opts)
+	case opts.format == FormatJSON:
+		encodeJSON(b, e, opts)
+	case opts.format == FormatLogfmt:
+		encodeLogfmt(b, e, opts)
+	case opts.format == formatMsgpack:
+		encodeMsgpack(b, e, opts)
+	default:
+		encodeText(b, e, opts)
+	}
+}
+
+// writeTime 按配置的时间格式写入 t，未配置时使用 layout
+func (opts encodeOptions) writeTime(b *bytes.Buffer, t time.Time, layout string) {
+	if opts.timeFormat != "" {
+		layout = opts.timeFormat
+	}
+	var scratch [64]byte
+	b.Write(t.AppendFormat(scratch[:0], layout))
+}
+
+func encodeText(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	opts.writeTime(b, e.Time, defaultTextTimeFormat)
+	b.WriteByte(' ')
+	b.WriteString(e.Level.String())
+	b.WriteByte(' ')
+	if e.Module != "" {
+		b.WriteByte('[')
+		b.WriteString(e.Module)
+		b.WriteString("] ")
+	}
+	if opts.escapeNewlines {
+		_, _ = newlineEscaper.WriteString(b, e.Message)
+	} else {
+		b.WriteString(e.Message)
+	}
+	writeTextFields(b, opts.identity)
+	writeTextFields(b, e.Fields)
+	if e.Caller != "" {
+		b.WriteString(" caller=")
+		b.WriteString(e.Caller)
+	}
+	if opts.escapeNewlines && e.Stack != "" {
+		b.WriteString(" stack=")
+		writeTextValue(b, e.Stack)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	if e.Stack != "" {
+		b.WriteString(e.Stack)
+		b.WriteByte('\n')
+	}
+}
+
+func encodeJSON(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	b.WriteString(`{"ts":"`)
+	opts.writeTime(b, e.Time, defaultJSONTimeFormat)
+	b.WriteString(`","level":`)
+	writeJSONString(b, e.Level.lowerString())
+	if e.Module != "" {
+		b.WriteString(`,"module":`)
+		writeJSONString(b, e.Module)
+	}
+	b.WriteString(`,"msg":`)
+	writeJSONString(b, e.Message)
+	if e.Caller != "" {
+		b.WriteString(`,"caller":`)
+		writeJSONString(b, e.Caller)
+	}
+	writeJSONFields(b, opts.identity)
+	writeJSONFields(b, e.Fields)
+	if e.Err != nil {
+		b.WriteString(`,"error":`)
+		writeJSONString(b, e.Err.Error())
+		if causes := errorCauses(e.Err); len(causes) > 0 {
+			b.WriteString(`,"cause":`)
+			writeJSONString(b, causes[len(causes)-1].Error())
+		}
+	}
+	if e.Stack != "" {
+		b.WriteString(`,"stack":`)
+		writeJSONString(b, e.Stack)
+	}
+	b.WriteString("}\n")
+}
+
+// writeJSONFields 以 ,"key":value 的形式写入字段，无法编码的值写为 fmt.Sprint 的结果
+func writeJSONFields(b *bytes.Buffer, fields []Field) {
+	var scratch [32]byte
+	for _, f := range fields {
+		b.WriteByte(',')
+		writeJSONString(b, f.Key)
+		b.WriteByte(':')
+		// 常见类型直接写入，避免 json.Marshal 的内存分配
+		switch v := fieldValue(f.Value).(type) {
+		case nil:
+			b.WriteString("null")
+		case string:
+			writeJSONString(b, v)
+		case bool:
+			b.Write(strconv.AppendBool(scratch[:0], v))
+		case int:
+			b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+		case int64:
+			b.Write(strconv.AppendInt(scratch[:0], v, 10))
+		case int32:
+			b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+		case uint:
+			b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+		case uint64:
+			b.Write(strconv.AppendUint(scratch[:0], v, 10))
+		case uint32:
+			b.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				writeJSONString(b, fmt.Sprint(v))
+				continue
+			}
+			b.Write(strconv.AppendFloat(scratch[:0], v, 'g', -1, 64))
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				writeJSONString(b, fmt.Sprint(f.Value))
+				continue
+			}
+			b.Write(data)
+		}
+	}
+}
+
+// writeTextFields 以 key=value 的形式写入字段，每个字段前有一个空格
+func writeTextFields(b *bytes.Buffer, fields []Field) {
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		writeTextValue(b, fieldValue(f.Value))
+	}
+}
+
+// writeTextValue 将字段值格式化为文本，包含空白、引号或等号时加上引号
+func writeTextValue(b *bytes.Buffer, v interface{}) {
+	var scratch [32]byte
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case int:
+		b.Write(strconv.AppendInt(scratch[:0], int64(val), 10))
+		return
+	case int64:
+		b.Write(strconv.AppendInt(scratch[:0], val, 10))
+		return
+	case bool:
+		b.Write(strconv.AppendBool(scratch[:0], val))
+		return
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		b.Write(strconv.AppendQuote(scratch[:0], s))
+		return
+	}
+	b.WriteString(s)
+}
+
+// writeJSONString 将 s 编码为 JSON 字符串写入 b，无效的 UTF-8 替换为 \ufffd
+func writeJSONString(b *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[c>>4])
+				b.WriteByte(hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(s[start:i])
+			b.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
+
+// caller 返回调用方的 文件:行号，跳过本包自身、通过 Writer 接入的标准库 log 包以及 panic 时 runtime 的栈帧
+func caller() string {
+	frame, ok := callerFrame()
+	if !ok {
+		return ""
+	}
+	return formatFrame(frame)
+}
+
+// callerFrame 返回调用方的栈帧，跳过的栈帧与 caller 相同
+func callerFrame() (runtime.Frame, bool) {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPath+".") && !strings.HasPrefix(frame.Function, "log.") &&
+			!strings.HasPrefix(frame.Function, "runtime.") {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// formatFrame 将栈帧格式化为 目录/文件:行号
+func formatFrame(frame runtime.Frame) string {
+	dir, file := filepath.Split(frame.File)
+	return filepath.Base(dir) + "/" + file + ":" + strconv.Itoa(frame.Line)
+}
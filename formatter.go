@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"sort"
+)
+
+// ShortLevelTags 是内置级别的单字母标签，可用于 TextFormatter.LevelTags
+var ShortLevelTags = map[Level]string{
+	LevelTrace:   "[T]",
+	LevelDebug:   "[D]",
+	LevelInfo:    "[I]",
+	LevelWarning: "[W]",
+	LevelError:   "[E]",
+}
+
+// TextFormatter 是可定制的文本格式，实现了 Encoder，通过 SetEncoder 使用，零值与默认的文本格式相同，
+// 例如输出 "15:04:05 [I] msg"：
+//
+//	SetEncoder(&TextFormatter{TimeFormat: "15:04:05", LevelTags: ShortLevelTags})
+type TextFormatter struct {
+	// TimeFormat 是时间格式，为空时使用 2006/01/02 15:04:05.000000
+	TimeFormat string
+	// LevelTags 是各级别输出的标签，未设置的级别使用级别名称，例如 INFO
+	LevelTags map[Level]string
+	// Separator 是时间、级别和日志内容之间的分隔符，为空时使用一个空格
+	Separator string
+	// SortFields 为 true 时字段按 key 排序输出，否则按附加的顺序输出
+	SortFields bool
+	// ShowCaller 为 true 时在字段之后输出 caller=文件:行号
+	ShowCaller bool
+}
+
+func (f *TextFormatter) Encode(b *bytes.Buffer, e *Entry) {
+	sep := f.Separator
+	if sep == "" {
+		sep = " "
+	}
+	layout := f.TimeFormat
+	if layout == "" {
+		layout = defaultTextTimeFormat
+	}
+	var scratch [64]byte
+	b.Write(e.Time.AppendFormat(scratch[:0], layout))
+	b.WriteString(sep)
+	if tag, ok := f.LevelTags[e.Level]; ok {
+		b.WriteString(tag)
+	} else {
+		b.WriteString(e.Level.String())
+	}
+	b.WriteString(sep)
+	if e.Module != "" {
+		b.WriteByte('[')
+		b.WriteString(e.Module)
+		b.WriteString("] ")
+	}
+	b.WriteString(e.Message)
+	// 使用自定义编码器时 error 不会展开到日志内容中，在这里按文本格式的规则拼接
+	if e.Err != nil {
+		b.WriteByte(' ')
+		b.WriteString(errorChain(e.Err))
+	}
+	fields := e.Fields
+	if f.SortFields {
+		fields = append([]Field(nil), fields...)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Key < fields[j].Key
+		})
+	}
+	writeTextFields(b, fields)
+	if f.ShowCaller && e.Caller != "" {
+		b.WriteString(" caller=")
+		b.WriteString(e.Caller)
+	}
+	b.WriteByte('\n')
+	if e.Stack != "" {
+		b.WriteString(e.Stack)
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,61 @@
+// Package ginlog 将 gin 的访问日志、错误和 panic 写入 go_logger：
+//
+//	r := gin.New()
+//	r.Use(ginlog.Middleware(logger.Default()), ginlog.Recovery(logger.Default()))
+//	ginlog.RedirectDefaultWriters(logger.Default())
+//
+// 本包是独立的 module，只有使用时才会引入 gin 的依赖。
+package ginlog
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nickham-su/go_logger"
+)
+
+// Middleware 返回记录访问日志的中间件，每个请求完成后以 l.AccessLevel() 输出一条日志，
+// 包含 method、path（路由模板）、status、latency、bytes 和 ip，c.Errors 中的错误以 ERROR 级别输出
+func Middleware(l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		l.At(l.AccessLevel()).With(
+			"method", c.Request.Method,
+			"path", path,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"bytes", c.Writer.Size(),
+			"ip", c.ClientIP(),
+		).Println(c.Request.Method, c.Request.URL.Path)
+		for _, err := range c.Errors {
+			l.Error.With("method", c.Request.Method, "path", path).Println(err.Err)
+		}
+	}
+}
+
+// Recovery 返回捕获 panic 的中间件，panic 的值和调用栈以 ERROR 级别输出，并返回 500
+func Recovery(l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		panicked := true
+		defer func() {
+			if panicked {
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		defer l.RecoverAndLog(false)
+		c.Next()
+		panicked = false
+	}
+}
+
+// RedirectDefaultWriters 将 gin 的调试信息和错误输出分别重定向到 l 的 Debug 和 Error 级别
+func RedirectDefaultWriters(l *logger.Logger) {
+	gin.DefaultWriter = l.Debug.Writer()
+	gin.DefaultErrorWriter = l.Error.Writer()
+}
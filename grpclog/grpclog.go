@@ -0,0 +1,158 @@
+// Package grpclog 提供记录 gRPC 调用日志的服务端和客户端拦截器：
+//
+//	s := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpclog.UnaryServerInterceptor(logger.Default())),
+//		grpc.StreamInterceptor(grpclog.StreamServerInterceptor(logger.Default())),
+//	)
+//
+// 每次调用结束后输出一条日志，包含 method、peer、code 和 duration，出错时附加错误。
+// 本包是独立的 module，只有使用时才会引入 gRPC 的依赖。
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Option 用于拦截器的配置项
+type Option func(*options)
+
+type options struct {
+	levelOf func(codes.Code) logger.Level
+}
+
+// WithCodeLevel 设置按状态码选择日志级别的函数
+func WithCodeLevel(f func(codes.Code) logger.Level) Option {
+	return func(o *options) {
+		if f != nil {
+			o.levelOf = f
+		}
+	}
+}
+
+// DefaultCodeLevel 是默认的日志级别：OK 为 Info，调用方的错误（参数错误、未找到、未授权等）为 Warning，其余为 Error
+func DefaultCodeLevel(code codes.Code) logger.Level {
+	switch code {
+	case codes.OK:
+		return logger.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.ResourceExhausted:
+		return logger.LevelWarning
+	}
+	return logger.LevelError
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{levelOf: DefaultCodeLevel}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor 返回记录一元调用的服务端拦截器
+func UnaryServerInterceptor(l *logger.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.log(l, ctx, "grpc server", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回记录流式调用的服务端拦截器
+func StreamServerInterceptor(l *logger.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		o.log(l, ss.Context(), "grpc server", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 返回记录一元调用的客户端拦截器
+func UnaryClientInterceptor(l *logger.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.logClient(l, ctx, cc, method, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor 返回记录流式调用的客户端拦截器，在流结束（RecvMsg 返回错误或 io.EOF）时输出日志
+func StreamClientInterceptor(l *logger.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			o.logClient(l, ctx, cc, method, start, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, done: func(err error) {
+			o.logClient(l, ctx, cc, method, start, err)
+		}}, nil
+	}
+}
+
+// clientStream 在流结束时调用一次 done
+type clientStream struct {
+	grpc.ClientStream
+	once sync.Once
+	done func(err error)
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.done(nil)
+				return
+			}
+			s.done(err)
+		})
+	}
+	return err
+}
+
+func (o *options) logClient(l *logger.Logger, ctx context.Context, cc *grpc.ClientConn, method string, start time.Time, err error) {
+	o.write(l, ctx, "grpc client", method, cc.Target(), start, err)
+}
+
+func (o *options) log(l *logger.Logger, ctx context.Context, kind, method string, start time.Time, err error) {
+	addr := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	o.write(l, ctx, kind, method, addr, start, err)
+}
+
+func (o *options) write(l *logger.Logger, ctx context.Context, kind, method, addr string, start time.Time, err error) {
+	code := status.Code(err)
+	level := o.levelOf(code)
+	lv := l.At(level).WithContext(ctx).With(
+		"method", method,
+		"peer", addr,
+		"code", code.String(),
+		"duration", time.Since(start),
+	)
+	if err != nil {
+		lv.With("error", err).Println(kind, method)
+		return
+	}
+	lv.Println(kind, method)
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// processStart 是进程启动（包初始化）的时间
+var processStart = time.Now()
+
+// SetFileHeader 设置默认实例新建日志文件时写入的文件头
+func SetFileHeader(header func() string) {
+	std.SetFileHeader(header)
+}
+
+// SetFileHeader 设置新建日志文件时在开头写入的内容，例如应用版本、git 提交、启动时间和主机名，用于排查时确认日志来自哪个版本，
+// 按天轮转和按大小切分后的新文件同样会写入，追加写入已存在的文件时不写入，header 在每次新建文件时调用，
+// 返回值末尾没有换行时自动添加，返回空字符串时不写入，传入 nil 取消，可以使用 DefaultFileHeader
+func (l *Logger) SetFileHeader(header func() string) {
+	if header == nil {
+		l.fileHeader.Store(nil)
+	} else {
+		l.fileHeader.Store(&header)
+	}
+}
+
+// DefaultFileHeader 返回以 # 开头的一行文件头，包含程序名称、模块版本、git 提交、进程启动时间、主机名和进程 ID，例如
+// # app=server version=v1.2.0 revision=3f2a9c1 started=2006-01-02T15:04:05+08:00 host=web-1 pid=1234
+func DefaultFileHeader() string {
+	var b strings.Builder
+	b.WriteString("# app=" + programName())
+	b.WriteString(buildVersion())
+	b.WriteString(" started=" + processStart.Format(time.RFC3339))
+	if host, err := os.Hostname(); err == nil {
+		b.WriteString(" host=" + host)
+	}
+	b.WriteString(" pid=" + pidString())
+	return b.String()
+}
+
+// buildVersion 返回 " version=v1.2.0 revision=3f2a9c1" 形式的模块版本和 git 提交，没有构建信息的部分省略
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		b.WriteString(" version=" + v)
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.WriteString(" revision=" + s.Value)
+		case "vcs.modified":
+			if s.Value == "true" {
+				b.WriteString(" modified=true")
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeHeader 在新建的空文件开头写入文件头，设置了加密密钥时同样加密，调用方需持有 f.mu
+func (f *fileSink) writeHeader() {
+	header := f.owner.fileHeader.Load()
+	if header == nil || f.file.size.Load() > 0 {
+		return
+	}
+	s := (*header)()
+	if s == "" {
+		return
+	}
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	_, _ = f.writeFile([]byte(s))
+}
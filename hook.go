@@ -0,0 +1,43 @@
+package logger
+
+// Hook 在每条日志编码之前被调用，可以读取或修改日志的内容
+type Hook interface {
+	Fire(e *Entry)
+}
+
+// HookFunc 将普通函数转换为 Hook
+type HookFunc func(e *Entry)
+
+func (f HookFunc) Fire(e *Entry) {
+	f(e)
+}
+
+// AddHook 为默认实例添加 Hook
+func AddHook(h Hook) {
+	std.AddHook(h)
+}
+
+// AddHook 添加 Hook，多个 Hook 按添加的顺序调用
+func (l *Logger) AddHook(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var hooks []Hook
+	if old := l.hooks.Load(); old != nil {
+		hooks = append(hooks, *old...)
+	}
+	hooks = append(hooks, h)
+	l.hooks.Store(&hooks)
+}
+
+// fireHooks 依次调用所有 Hook
+func (l *Logger) fireHooks(e *Entry) {
+	hooks := l.hooks.Load()
+	if hooks == nil {
+		return
+	}
+	// Fields 可能与 With 创建的 fieldLogger 共用底层数组，复制一份以免 Hook 的修改互相影响
+	e.Fields = append([]Field(nil), e.Fields...)
+	for _, h := range *hooks {
+		h.Fire(e)
+	}
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// accessRank 是 ACCESS 级别的 rank，介于 INFO 和 WARNING 之间，访问日志不会被当作错误处理
+const accessRank = 1
+
+var (
+	accessOnce  sync.Once
+	accessLevel Level
+)
+
+// defaultAccessLevel 返回访问日志默认使用的 ACCESS 级别，第一次调用时注册，
+// 已经通过 RegisterLevel 注册了 ACCESS 时使用已有的级别，rank 已被其他级别占用时使用 INFO
+func defaultAccessLevel() Level {
+	accessOnce.Do(func() {
+		if level, ok := lookupLevel("ACCESS"); ok {
+			accessLevel = level
+			return
+		}
+		level, err := RegisterLevel("ACCESS", accessRank)
+		if err != nil {
+			level = LevelInfo
+		}
+		accessLevel = level
+	})
+	return accessLevel
+}
+
+// SetAccessLevel 设置默认实例的访问日志使用的级别
+func SetAccessLevel(level Level) {
+	std.SetAccessLevel(level)
+}
+
+// SetAccessLevel 设置 HTTPMiddleware 以及 ginlog、echolog 的访问日志使用的级别，
+// 默认为 rank 1 的 ACCESS 级别，写入单独的 {date}.access.log。访问日志同样受 SetLevel 的最低级别过滤，
+// 例如 SetLevel(LevelWarning) 之后 ACCESS 级别的访问日志不再输出，需要保留时可以设置为 WARNING 及以上的级别
+func (l *Logger) SetAccessLevel(level Level) {
+	l.accessLevel.Store(&level)
+}
+
+// AccessLevel 返回默认实例的访问日志使用的级别
+func AccessLevel() Level {
+	return std.AccessLevel()
+}
+
+// AccessLevel 返回访问日志使用的级别，未调用 SetAccessLevel 时注册并返回 ACCESS 级别
+func (l *Logger) AccessLevel() Level {
+	if level := l.accessLevel.Load(); level != nil {
+		return *level
+	}
+	return defaultAccessLevel()
+}
+
+// HTTPMiddleware 返回将访问日志写入默认实例的 http.Handler
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return std.HTTPMiddleware(next)
+}
+
+// HTTPMiddleware 返回记录访问日志的 http.Handler，每个请求在处理完成后以 AccessLevel 输出一条日志，
+// 包含 method、path、status、latency、bytes、ip 和 request_id，next 发生 panic 时 status 记为 500，
+// 请求 ID 取自 X-Request-Id 请求头，没有时生成新的 ID，并通过 r.Context() 传给 next、写入响应头
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := requestID(r)
+		r = r.WithContext(ContextWithRequestID(r.Context(), id))
+		w.Header().Set(RequestIDHeader, id)
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		panicked := true
+		defer func() {
+			if panicked {
+				rw.status = http.StatusInternalServerError
+			}
+			l.At(l.AccessLevel()).WithContext(r.Context()).With(
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rw.status,
+				"latency", time.Since(start),
+				"bytes", rw.bytes,
+				"ip", clientIP(r),
+			).Println(r.Method, r.URL.Path)
+		}()
+		next.ServeHTTP(rw, r)
+		panicked = false
+	})
+}
+
+// responseWriter 记录响应的状态码和写入的字节数
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+// Flush 使流式响应能够经过中间件刷新到客户端
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Hijack 使 WebSocket 等需要接管连接的处理函数能够经过中间件，接管之后状态码记为 101
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack：%T", w.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap 使 http.ResponseController 能够访问原始的 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// clientIP 返回客户端的地址，优先使用 X-Forwarded-For 中的第一个地址
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		ip, _, _ := strings.Cut(fwd, ",")
+		return strings.TrimSpace(ip)
+	}
+	if ip := r.Header.Get("X-Real-Ip"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// LevelHandler 返回查看和修改默认实例最低输出级别的 http.Handler
+func LevelHandler() http.Handler {
+	return std.LevelHandler()
+}
+
+// levelPayload 是 LevelHandler 请求和响应的内容
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler 返回查看和修改最低输出级别的 http.Handler：GET 返回 {"level":"info"}，
+// PUT 接受相同格式的 JSON 或 level 参数修改级别并返回修改后的级别
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var p levelPayload
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+				if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+					writeLevelError(w, http.StatusBadRequest, err)
+					return
+				}
+			} else {
+				p.Level = r.FormValue("level")
+			}
+			level, err := ParseLevel(p.Level)
+			if err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+			l.SetLevel(level)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("不支持的请求方法：%s", r.Method))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelPayload{Level: l.GetLevel().lowerString()})
+	})
+}
+
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
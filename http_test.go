@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newHTTPTestLogger 返回记录访问日志的实例
+func newHTTPTestLogger(t *testing.T) (*Logger, *entryRecorder) {
+	t.Helper()
+	l := New(WithDir(t.TempDir()))
+	t.Cleanup(func() { _ = l.Close() })
+	r := &entryRecorder{}
+	l.AddHook(r)
+	return l, r
+}
+
+func TestHTTPMiddlewareFlush(t *testing.T) {
+	l, r := newHTTPTestLogger(t)
+	h := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Error("ResponseWriter 没有实现 http.Flusher")
+			return
+		}
+		f.Flush()
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if !rec.Flushed {
+		t.Error("Flush 没有传递给原始的 ResponseWriter")
+	}
+	if status, _ := fieldValueOf(r.last(t), "status"); status != http.StatusOK {
+		t.Errorf("status 为 %v，应当为 200", status)
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	l, r := newHTTPTestLogger(t)
+	// 接管之后 httptest.Server 不再等待处理函数返回，通过 done 等待中间件输出访问日志
+	done := make(chan struct{})
+	mw := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("ResponseWriter 没有实现 http.Hijacker")
+			return
+		}
+		conn, buf, err := h.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = buf.Flush()
+	}))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		mw.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("响应的状态码为 %d，应当为 101", resp.StatusCode)
+	}
+	<-done
+	if status, _ := fieldValueOf(r.last(t), "status"); status != http.StatusSwitchingProtocols {
+		t.Errorf("status 为 %v，应当为 101", status)
+	}
+}
+
+func TestHTTPMiddlewareHijackNotSupported(t *testing.T) {
+	l, _ := newHTTPTestLogger(t)
+	h := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		if err == nil || !strings.Contains(err.Error(), "不支持 Hijack") {
+			t.Errorf("原始的 ResponseWriter 不支持 Hijack 时应当返回错误，得到 %v", err)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ws", nil))
+}
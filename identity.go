@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+)
+
+// identity 是每条日志附加的进程标识，修改时整体替换
+type identity struct {
+	app      string
+	hostname bool
+	pid      bool
+	// fields 是按 app、host、pid 顺序生成的字段
+	fields []Field
+}
+
+// SetAppName 设置默认实例的应用名称
+func SetAppName(name string) {
+	std.SetAppName(name)
+}
+
+// SetAppName 设置应用名称，不为空时每条日志附加 app 字段，文件名格式中的 {app} 也使用该名称，
+// 为空时 {app} 使用当前程序的名称
+func (l *Logger) SetAppName(name string) {
+	l.updateIdentity(func(id *identity) {
+		id.app = name
+	})
+	l.rotate()
+}
+
+// SetReportHostname 设置默认实例是否输出主机名
+func SetReportHostname(report bool) {
+	std.SetReportHostname(report)
+}
+
+// SetReportHostname 设置是否在每条日志中附加 host 字段，用于汇总多台机器的日志后区分来源
+func (l *Logger) SetReportHostname(report bool) {
+	l.updateIdentity(func(id *identity) {
+		id.hostname = report
+	})
+}
+
+// SetReportPID 设置默认实例是否输出进程号
+func SetReportPID(report bool) {
+	std.SetReportPID(report)
+}
+
+// SetReportPID 设置是否在每条日志中附加 pid 字段
+func (l *Logger) SetReportPID(report bool) {
+	l.updateIdentity(func(id *identity) {
+		id.pid = report
+	})
+}
+
+func (l *Logger) updateIdentity(update func(id *identity)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var id identity
+	if old := l.identity.Load(); old != nil {
+		id = *old
+	}
+	update(&id)
+	id.fields = nil
+	if id.app != "" {
+		id.fields = append(id.fields, Field{Key: "app", Value: id.app})
+	}
+	if id.hostname {
+		host, _ := os.Hostname()
+		id.fields = append(id.fields, Field{Key: "host", Value: host})
+	}
+	if id.pid {
+		id.fields = append(id.fields, Field{Key: "pid", Value: os.Getpid()})
+	}
+	l.identity.Store(&id)
+}
+
+// identityFields 返回每条日志需要附加的进程标识字段
+func (l *Logger) identityFields() []Field {
+	if id := l.identity.Load(); id != nil {
+		return id.fields
+	}
+	return nil
+}
+
+// appName 返回 SetAppName 设置的名称，未设置时返回当前程序的名称
+func (l *Logger) appName() string {
+	if id := l.identity.Load(); id != nil && id.app != "" {
+		return id.app
+	}
+	return programName()
+}
+
+// pidString 返回当前进程号
+func pidString() string {
+	return strconv.Itoa(os.Getpid())
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// internalDropInterval 是报告丢弃日志的最短间隔，队列持续已满时避免诊断输出本身刷屏
+const internalDropInterval = time.Second
+
+// internalLogger 向 SetInternalLogger 设置的 writer 输出诊断信息
+type internalLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+	// dropped 是尚未报告的丢弃条数，lastDrop 是上次报告丢弃的时间（UnixNano）
+	dropped  atomic.Uint64
+	lastDrop atomic.Int64
+}
+
+// SetInternalLogger 设置默认实例输出诊断信息的 writer
+func SetInternalLogger(w io.Writer) {
+	std.SetInternalLogger(w)
+}
+
+// SetInternalLogger 设置输出本包自身问题的 writer，例如 os.Stderr，每个问题输出为一行文本，
+// 包括打开、轮转、压缩、清理日志文件失败，writer 写入失败，以及异步队列或网络输出已满而丢弃日志（每秒最多报告一次合计的条数），
+// 诊断信息不经过日志本身，在日志文件不可用时仍然可以看到，w 为 nil 时关闭，SetErrorHandler 的回调不受影响
+func (l *Logger) SetInternalLogger(w io.Writer) {
+	if w == nil {
+		l.internal.Store(nil)
+		return
+	}
+	l.internal.Store(&internalLogger{w: w})
+}
+
+// internalf 在设置了 SetInternalLogger 时输出一行诊断信息
+func (l *Logger) internalf(format string, v ...interface{}) {
+	if il := l.internal.Load(); il != nil {
+		il.printf(format, v...)
+	}
+}
+
+func (il *internalLogger) printf(format string, v ...interface{}) {
+	line := time.Now().Format(time.RFC3339) + " go_logger: " + fmt.Sprintf(format, v...) + "\n"
+	il.mu.Lock()
+	defer il.mu.Unlock()
+	_, _ = io.WriteString(il.w, line)
+}
+
+// recordDropped 记录因队列已满而丢弃的 n 条日志
+func (l *Logger) recordDropped(n uint64) {
+	l.metrics.dropped.Add(n)
+	if il := l.internal.Load(); il != nil {
+		il.dropped.Add(n)
+		il.reportDropped(false)
+	}
+}
+
+// reportDroppedNow 报告尚未报告的丢弃条数，在 Close 时调用
+func (l *Logger) reportDroppedNow() {
+	if il := l.internal.Load(); il != nil {
+		il.reportDropped(true)
+	}
+}
+
+// reportDropped 报告尚未报告的丢弃条数，force 为 false 时距上次报告不足 internalDropInterval 则留到之后报告
+func (il *internalLogger) reportDropped(force bool) {
+	now := time.Now().UnixNano()
+	last := il.lastDrop.Load()
+	if !force && now-last < int64(internalDropInterval) {
+		return
+	}
+	if !il.lastDrop.CompareAndSwap(last, now) {
+		return
+	}
+	if n := il.dropped.Swap(0); n > 0 {
+		il.printf("队列已满，丢弃了 %d 条日志", n)
+	}
+}
+
+// writerName 返回诊断信息中 writer 的名称
+func writerName(w io.Writer) string {
+	switch w := w.(type) {
+	case *fileSink:
+		return "日志文件"
+	case *os.File:
+		return " " + w.Name() + " "
+	}
+	return fmt.Sprintf(" %T ", w)
+}
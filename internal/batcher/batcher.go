@@ -0,0 +1,179 @@
+// Package batcher 是 kafka、loki、dbsink 共用的批量发送队列，外部代码无法导入
+package batcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxPending 是等待发送的最大条数，超出的条目被丢弃并通过错误回调报告
+const MaxPending = 10000
+
+// permanentError 是重试也不会成功的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将 err 标记为重试也不会成功的错误，发送函数返回这样的错误时这一批被丢弃，不再重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Batcher 收集条目并由唯一的后台 goroutine 按批发送，凑满 batchSize 条、等待超过 interval 或调用 Flush 时发送，
+// 添加条目的调用方不会等待发送，发送失败的一批保留在队列开头，在下一个 interval 重试，
+// 发送函数返回 Permanent 标记的错误时丢弃这一批
+type Batcher[T any] struct {
+	batchSize int
+	interval  time.Duration
+	send      func([]T) error
+	onError   func(error)
+
+	mu      sync.Mutex
+	pending []T
+	// dropped 是等待发送的条目超过 MaxPending 后丢弃的条数
+	dropped int
+	// sendMu 保证各批按顺序发送，Close 之后 Flush 直接发送时与 Close 互斥
+	sendMu sync.Mutex
+	// failing 为 true 时上一次发送失败，只在 interval 到达或调用 Flush 时重试，由 run 独占
+	failing bool
+
+	// wake 在凑满一批时通知后台 goroutine，flush 用于 Flush 请求后台 goroutine 立即发送
+	wake      chan struct{}
+	flush     chan chan error
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New 创建 Batcher 并启动后台发送的 goroutine，send 发送一批条目，onError 接收发送和丢弃的错误
+func New[T any](batchSize int, interval time.Duration, send func([]T) error, onError func(error)) *Batcher[T] {
+	b := &Batcher[T]{
+		batchSize: batchSize,
+		interval:  interval,
+		send:      send,
+		onError:   onError,
+		wake:      make(chan struct{}, 1),
+		flush:     make(chan chan error),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add 将条目加入队列，凑满一批时通知后台 goroutine 发送
+func (b *Batcher[T]) Add(item T) {
+	b.mu.Lock()
+	if len(b.pending) < MaxPending {
+		b.pending = append(b.pending, item)
+	} else {
+		b.dropped++
+	}
+	full := len(b.pending) >= b.batchSize
+	b.mu.Unlock()
+	if full {
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush 立即发送已收集的条目并等待发送完成，返回第一个错误
+func (b *Batcher[T]) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case b.flush <- reply:
+		return <-reply
+	case <-b.done:
+		_, err := b.sendPending()
+		return err
+	}
+}
+
+// Close 停止后台发送并发送剩余的条目
+func (b *Batcher[T]) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		<-b.done
+	})
+	_, err := b.sendPending()
+	return err
+}
+
+// sendPending 按批发送队列中的条目，返回第一个错误，遇到可以重试的错误时将这一批放回队列开头并停止，requeued 为 true
+func (b *Batcher[T]) sendPending() (requeued bool, firstErr error) {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+	for {
+		b.mu.Lock()
+		n := len(b.pending)
+		if n > b.batchSize {
+			n = b.batchSize
+		}
+		batch := b.pending[:n:n]
+		b.pending = b.pending[n:]
+		dropped := b.dropped
+		b.dropped = 0
+		b.mu.Unlock()
+		if dropped > 0 {
+			b.reportError(fmt.Errorf("等待发送的日志超过 %d 条，丢弃了 %d 条", MaxPending, dropped), &firstErr)
+		}
+		if n == 0 {
+			return false, firstErr
+		}
+		err := b.send(batch)
+		if err == nil {
+			continue
+		}
+		b.reportError(err, &firstErr)
+		var permanent *permanentError
+		if !errors.As(err, &permanent) {
+			b.mu.Lock()
+			b.pending = append(batch, b.pending...)
+			b.mu.Unlock()
+			return true, firstErr
+		}
+	}
+}
+
+// reportError 调用错误回调，并在 *first 为 nil 时记录 err
+func (b *Batcher[T]) reportError(err error, first *error) {
+	if *first == nil {
+		*first = err
+	}
+	if b.onError != nil {
+		b.onError(err)
+	}
+}
+
+// run 是唯一发送条目的 goroutine，上一次发送失败时不因凑满一批而立即重试，避免对方不可用时反复请求
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case reply := <-b.flush:
+			var err error
+			b.failing, err = b.sendPending()
+			reply <- err
+		case <-b.wake:
+			if !b.failing {
+				b.failing, _ = b.sendPending()
+			}
+		case <-ticker.C:
+			b.failing, _ = b.sendPending()
+		}
+	}
+}
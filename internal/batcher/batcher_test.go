@@ -0,0 +1,141 @@
+package batcher
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder 记录发送成功的条目，fail 不为 nil 时发送返回该错误
+type recorder struct {
+	mu    sync.Mutex
+	sent  []int
+	calls int
+	fail  error
+}
+
+func (r *recorder) send(batch []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.fail != nil {
+		return r.fail
+	}
+	r.sent = append(r.sent, batch...)
+	return nil
+}
+
+func (r *recorder) setFail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fail = err
+}
+
+func (r *recorder) result() ([]int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.sent...), r.calls
+}
+
+func TestRetryKeepsOrder(t *testing.T) {
+	r := &recorder{fail: errors.New("unavailable")}
+	b := New(2, time.Hour, r.send, nil)
+	defer b.Close()
+	for i := 1; i <= 3; i++ {
+		b.Add(i)
+	}
+	if err := b.Flush(); err == nil {
+		t.Fatal("发送失败时 Flush 应当返回错误")
+	}
+	r.setFail(nil)
+	b.Add(4)
+	if err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if sent, _ := r.result(); !reflect.DeepEqual(sent, []int{1, 2, 3, 4}) {
+		t.Errorf("发送的条目为 %v，应当按顺序重试为 [1 2 3 4]", sent)
+	}
+}
+
+func TestPermanentErrorDropsBatch(t *testing.T) {
+	r := &recorder{fail: Permanent(errors.New("bad request"))}
+	var reported []error
+	b := New(10, time.Hour, r.send, func(err error) { reported = append(reported, err) })
+	defer b.Close()
+	b.Add(1)
+	b.Add(2)
+	if err := b.Flush(); err == nil {
+		t.Fatal("发送失败时 Flush 应当返回错误")
+	}
+	r.setFail(nil)
+	b.Add(3)
+	if err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if sent, _ := r.result(); !reflect.DeepEqual(sent, []int{3}) {
+		t.Errorf("发送的条目为 %v，无法重试的一批应当被丢弃", sent)
+	}
+	if len(reported) != 1 {
+		t.Errorf("错误回调收到 %d 个错误，应当为 1 个", len(reported))
+	}
+}
+
+func TestFailingWaitsForInterval(t *testing.T) {
+	r := &recorder{fail: errors.New("unavailable")}
+	b := New(1, time.Hour, r.send, nil)
+	defer b.Close()
+	b.Add(0)
+	_ = b.Flush()
+	_, before := r.result()
+	for i := 1; i <= 100; i++ {
+		b.Add(i)
+	}
+	// 通过 Flush 等待后台 goroutine 处理完之前的通知
+	_ = b.Flush()
+	if _, calls := r.result(); calls != before+1 {
+		t.Errorf("失败后发送了 %d 次，凑满一批时不应当立即重试，只有 Flush 应当发送 1 次", calls-before)
+	}
+}
+
+func TestMaxPending(t *testing.T) {
+	r := &recorder{fail: errors.New("unavailable")}
+	var mu sync.Mutex
+	var reported []error
+	b := New(MaxPending+1, time.Hour, r.send, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	})
+	for i := 0; i < MaxPending+5; i++ {
+		b.Add(i)
+	}
+	r.setFail(nil)
+	if err := b.Close(); err == nil {
+		t.Fatal("丢弃条目时应当返回错误")
+	}
+	if sent, _ := r.result(); len(sent) != MaxPending {
+		t.Errorf("发送了 %d 条，应当为 %d 条", len(sent), MaxPending)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 {
+		t.Errorf("错误回调收到 %d 个错误，应当为 1 个", len(reported))
+	}
+}
+
+func TestFlushAfterClose(t *testing.T) {
+	r := &recorder{}
+	b := New(10, time.Hour, r.send, nil)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b.Add(1)
+	if err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if sent, _ := r.result(); !reflect.DeepEqual(sent, []int{1}) {
+		t.Errorf("Close 之后 Flush 应当直接发送，发送的条目为 %v", sent)
+	}
+}
@@ -0,0 +1,5 @@
+// Package testhook 保存只供本 module 的测试工具使用的钩子，外部代码无法导入
+package testhook
+
+// ResetDefault 关闭并重新创建 logger 包的默认实例，由 logger 包在初始化时设置，通过 logtest.ResetDefault 调用
+var ResetDefault func() error
@@ -0,0 +1,51 @@
+package journald
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// conn 是发送到 journald 的 unixgram 连接
+type conn struct {
+	sock *net.UnixConn
+	addr *net.UnixAddr
+}
+
+func dial(path string) (*conn, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	sock, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &conn{sock: sock, addr: &net.UnixAddr{Name: path, Net: "unixgram"}}, nil
+}
+
+// send 发送一个数据报，超过 socket 的大小限制时写入临时文件后传递文件描述符
+func (c *conn) send(data []byte) error {
+	_, _, err := c.sock.WriteMsgUnix(data, nil, c.addr)
+	if err == nil || !(errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)) {
+		return err
+	}
+	f, err := os.CreateTemp("/dev/shm", "journal.")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// journald 要求文件没有其他链接，传递前删除文件名
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	_, _, err = c.sock.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), c.addr)
+	return err
+}
+
+func (c *conn) close() error {
+	return c.sock.Close()
+}
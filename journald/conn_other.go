@@ -0,0 +1,20 @@
+//go:build !linux
+
+package journald
+
+import "errors"
+
+// conn 在非 Linux 系统上不可用
+type conn struct{}
+
+func dial(path string) (*conn, error) {
+	return nil, errors.New("journald 只支持 Linux")
+}
+
+func (c *conn) send(data []byte) error {
+	return nil
+}
+
+func (c *conn) close() error {
+	return nil
+}
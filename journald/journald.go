@@ -0,0 +1,237 @@
+// Package journald 通过 systemd-journald 的原生协议发送 go_logger 的日志，
+// 级别转换为 PRIORITY，调用方、错误、模块和 With 添加的字段作为结构化字段写入 journal：
+//
+//	j, err := journald.New(journald.WithIdentifier("order"))
+//	if err == nil {
+//		j.Attach(logger.Default())
+//		defer j.Close()
+//	}
+//
+// journald 的写入通过 Hook 完成，不影响已有的日志文件，文件可以作为备份继续保留。
+// 只支持 Linux，其他系统上 New 返回错误。
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nickham-su/go_logger"
+)
+
+// DefaultSocket 是 journald 接收原生协议的 socket 路径
+const DefaultSocket = "/run/systemd/journal/socket"
+
+// syslog 的严重级别，用于 PRIORITY 字段
+const (
+	priErr     = 3
+	priWarning = 4
+	priInfo    = 6
+	priDebug   = 7
+)
+
+// Option 用于 New 的配置项
+type Option func(*Journal)
+
+// WithIdentifier 设置 SYSLOG_IDENTIFIER 字段，即 journalctl -t 使用的标识，默认为程序名
+func WithIdentifier(id string) Option {
+	return func(j *Journal) {
+		j.identifier = id
+	}
+}
+
+// WithSocket 设置 journald 的 socket 路径，默认为 DefaultSocket
+func WithSocket(path string) Option {
+	return func(j *Journal) {
+		if path != "" {
+			j.socket = path
+		}
+	}
+}
+
+// WithFields 添加每条日志都带有的字段，字段名会按 journald 的要求转换为大写
+func WithFields(fields map[string]string) Option {
+	return func(j *Journal) {
+		for k, v := range fields {
+			if name := fieldName(k); name != "" {
+				j.fields = append(j.fields, [2]string{name, v})
+			}
+		}
+	}
+}
+
+// WithErrorHandler 设置发送失败时的回调，默认忽略错误
+func WithErrorHandler(h func(err error)) Option {
+	return func(j *Journal) {
+		j.onError = h
+	}
+}
+
+// Journal 将日志发送到 journald
+type Journal struct {
+	identifier string
+	socket     string
+	fields     [][2]string
+	onError    func(err error)
+
+	conn   *conn
+	closed atomic.Bool
+}
+
+// Available 判断 journald 的 socket 是否存在，可以用来决定是否启用 journald
+func Available() bool {
+	_, err := os.Stat(DefaultSocket)
+	return err == nil
+}
+
+// New 连接 journald，socket 不存在或者不是 Linux 时返回错误
+func New(opts ...Option) (*Journal, error) {
+	j := &Journal{
+		identifier: filepath.Base(os.Args[0]),
+		socket:     DefaultSocket,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	c, err := dial(j.socket)
+	if err != nil {
+		return nil, err
+	}
+	j.conn = c
+	return j, nil
+}
+
+// Attach 为 l 添加将日志发送到 journald 的 Hook
+func (j *Journal) Attach(l *logger.Logger) {
+	l.AddHook(j)
+}
+
+// Close 关闭连接，之后的日志不再发送到 journald
+func (j *Journal) Close() error {
+	if j.closed.Swap(true) {
+		return nil
+	}
+	return j.conn.close()
+}
+
+// Fire 实现 logger.Hook，将一条日志发送到 journald
+func (j *Journal) Fire(e *logger.Entry) {
+	if j.closed.Load() {
+		return
+	}
+	if err := j.conn.send(j.encode(e)); err != nil && j.onError != nil {
+		j.onError(err)
+	}
+}
+
+// encode 将 e 编码为原生协议的一个数据报
+func (j *Journal) encode(e *logger.Entry) []byte {
+	var b bytes.Buffer
+	writeField(&b, "MESSAGE", e.Message)
+	writeField(&b, "PRIORITY", strconv.Itoa(priority(e.Level)))
+	writeField(&b, "LEVEL", e.Level.String())
+	if j.identifier != "" {
+		writeField(&b, "SYSLOG_IDENTIFIER", j.identifier)
+	}
+	if e.Module != "" {
+		writeField(&b, "MODULE", e.Module)
+	}
+	if e.Caller != "" {
+		file, line := e.Caller, ""
+		if i := strings.LastIndexByte(e.Caller, ':'); i >= 0 {
+			file, line = e.Caller[:i], e.Caller[i+1:]
+		}
+		writeField(&b, "CODE_FILE", file)
+		if line != "" {
+			writeField(&b, "CODE_LINE", line)
+		}
+	}
+	if e.Err != nil {
+		writeField(&b, "ERROR", e.Err.Error())
+	}
+	if e.Stack != "" {
+		writeField(&b, "STACK", e.Stack)
+	}
+	for _, f := range j.fields {
+		writeField(&b, f[0], f[1])
+	}
+	for _, f := range e.Fields {
+		if name := fieldName(f.Key); name != "" {
+			writeField(&b, name, value(f.Value))
+		}
+	}
+	return b.Bytes()
+}
+
+// priority 将日志级别转换为 syslog 的严重级别，自定义级别按 Rank 归入相邻的内置级别
+func priority(level logger.Level) int {
+	switch rank := level.Rank(); {
+	case rank < logger.LevelInfo.Rank():
+		return priDebug
+	case rank < logger.LevelWarning.Rank():
+		return priInfo
+	case rank < logger.LevelError.Rank():
+		return priWarning
+	default:
+		return priErr
+	}
+}
+
+// writeField 写入一个字段，值中包含换行时使用带 64 位小端长度的二进制形式
+func writeField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.Write(size[:])
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// fieldName 将字段名转换为 journald 接受的形式：大写字母、数字和下划线，
+// 不以下划线或数字开头，最长 64 个字符，无法转换时返回空字符串
+func fieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	s := strings.TrimLeft(string(name), "_")
+	if s == "" {
+		return ""
+	}
+	if s[0] >= '0' && s[0] <= '9' {
+		s = "F_" + s
+	}
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	return s
+}
+
+// value 将字段的值转换为字符串，func() string 在此时才调用
+func value(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case func() string:
+		return val()
+	case []byte:
+		return string(val)
+	}
+	return fmt.Sprint(v)
+}
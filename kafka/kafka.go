@@ -0,0 +1,152 @@
+// Package kafka 将 go_logger 的日志批量发送到 Kafka 的 topic。
+//
+// 本包不依赖具体的 Kafka 客户端，使用方用自己的客户端（例如 sarama、franz-go）实现 Producer 即可：
+//
+//	w := kafka.NewWriter(producer, "app-logs", kafka.WithServiceKey("order"))
+//	w.Attach(logger.Default())
+//	defer w.Close()
+package kafka
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+	"github.com/nickham-su/go_logger/internal/batcher"
+)
+
+// Message 是发送到 Kafka 的一条消息，Value 为按 logger 的格式编码后的一行日志，不含末尾的换行符
+type Message struct {
+	Key   []byte
+	Value []byte
+}
+
+// Producer 将一批消息发送到 topic
+type Producer interface {
+	SendMessages(topic string, msgs []Message) error
+}
+
+// Option 用于 NewWriter 的配置项
+type Option func(*Writer)
+
+// WithLevelKey 使用日志级别作为消息的 key，这是默认的行为
+func WithLevelKey() Option {
+	return func(w *Writer) {
+		w.service = ""
+	}
+}
+
+// WithServiceKey 使用服务名作为消息的 key
+func WithServiceKey(service string) Option {
+	return func(w *Writer) {
+		w.service = service
+	}
+}
+
+// WithBatchSize 设置每批发送的最大消息数，默认为 100
+func WithBatchSize(n int) Option {
+	return func(w *Writer) {
+		if n > 0 {
+			w.batchSize = n
+		}
+	}
+}
+
+// WithBatchTimeout 设置未凑满一批时的最长等待时间，默认为 1 秒
+func WithBatchTimeout(d time.Duration) Option {
+	return func(w *Writer) {
+		if d > 0 {
+			w.batchTimeout = d
+		}
+	}
+}
+
+// WithErrorHandler 设置发送失败时的回调，默认输出到 os.Stderr
+func WithErrorHandler(h func(err error)) Option {
+	return func(w *Writer) {
+		w.onError = h
+	}
+}
+
+// Writer 收集日志并按批发送到 Kafka，凑满 batchSize 条或等待超过 batchTimeout 时由后台 goroutine 发送，
+// 写入日志的调用方不会等待 Kafka，发送失败的一批消息保留在队列开头，在下一个 batchTimeout 重试，
+// 等待发送的消息超过 10000 条时丢弃新的消息
+type Writer struct {
+	producer     Producer
+	topic        string
+	service      string
+	batchSize    int
+	batchTimeout time.Duration
+	onError      func(err error)
+	batch        *batcher.Batcher[Message]
+}
+
+// NewWriter 创建发送到 topic 的 Writer，并启动按 batchTimeout 定时发送的 goroutine
+func NewWriter(producer Producer, topic string, opts ...Option) *Writer {
+	w := &Writer{
+		producer:     producer,
+		topic:        topic,
+		batchSize:    100,
+		batchTimeout: time.Second,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.batch = batcher.New(w.batchSize, w.batchTimeout, func(msgs []Message) error {
+		return w.producer.SendMessages(w.topic, msgs)
+	}, w.reportError)
+	return w
+}
+
+// Attach 将 Writer 添加到 l 的每个级别
+func (w *Writer) Attach(l *logger.Logger) {
+	for _, level := range logger.Levels() {
+		l.AppendWriterFor(level, w.For(level))
+	}
+}
+
+// For 返回写入 level 级别日志的 io.Writer，用于 Logger.AppendWriterFor
+func (w *Writer) For(level logger.Level) io.Writer {
+	key := w.service
+	if key == "" {
+		key = level.String()
+	}
+	return &levelWriter{w: w, key: []byte(key)}
+}
+
+// Flush 立即发送已收集的消息并等待发送完成
+func (w *Writer) Flush() error {
+	return w.batch.Flush()
+}
+
+// Close 停止后台发送并发送剩余的消息
+func (w *Writer) Close() error {
+	return w.batch.Close()
+}
+
+func (w *Writer) reportError(err error) {
+	if w.onError != nil {
+		w.onError(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "kafka: %v\n", err)
+}
+
+// levelWriter 为写入的每行日志附加 key
+type levelWriter struct {
+	w   *Writer
+	key []byte
+}
+
+func (lw *levelWriter) Write(p []byte) (int, error) {
+	lw.w.batch.Add(Message{Key: lw.key, Value: append([]byte(nil), bytes.TrimSuffix(p, []byte("\n"))...)})
+	return len(p), nil
+}
+
+// Flush 使 Logger.Sync 能够立即发送已收集的消息
+func (lw *levelWriter) Flush() error {
+	return lw.w.Flush()
+}
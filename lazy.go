@@ -0,0 +1,30 @@
+package logger
+
+// Lazy 输出 f 返回的内容，只有该级别需要输出时才调用 f，用于开销较大的日志内容
+func (l *logger) Lazy(f func() string) {
+	l.println(nil, []interface{}{f})
+}
+
+func (l *fieldLogger) Lazy(f func() string) {
+	l.logger.println(l, []interface{}{f})
+}
+
+// evalLazy 将参数中的 func() string 替换为其返回值，没有时直接返回 v，
+// 因此 Println、Printf 的参数以及 With 的值都可以传入 func() string 延迟生成
+func evalLazy(v []interface{}) []interface{} {
+	var out []interface{}
+	for i, arg := range v {
+		f, ok := arg.(func() string)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]interface{}(nil), v...)
+		}
+		out[i] = f()
+	}
+	if out == nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// levelInfo 是一个日志级别的名称和排序值
+type levelInfo struct {
+	name string
+	// lower 是小写的名称，用于文件名和 JSON 格式
+	lower string
+	rank  int
+}
+
+var (
+	// levels 保存所有级别，只在 RegisterLevel 中整体替换，内置级别的 rank 与 slog 的级别相同
+	levels = func() *atomic.Pointer[map[Level]levelInfo] {
+		p := new(atomic.Pointer[map[Level]levelInfo])
+		p.Store(&map[Level]levelInfo{
+			LevelTrace:   {name: "TRACE", lower: "trace", rank: -8},
+			LevelDebug:   {name: "DEBUG", lower: "debug", rank: -4},
+			LevelInfo:    {name: "INFO", lower: "info", rank: 0},
+			LevelWarning: {name: "WARNING", lower: "warning", rank: 4},
+			LevelError:   {name: "ERROR", lower: "error", rank: 8},
+		})
+		return p
+	}()
+	registerMu sync.Mutex
+	// levelNameRegexp 限制级别名称的字符，名称的小写形式会用在日志文件名中
+	levelNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+)
+
+func levelTable() map[Level]levelInfo {
+	return *levels.Load()
+}
+
+// RegisterLevel 注册一个新的日志级别并返回它，rank 决定过滤时的高低，内置级别的 rank 为
+// TRACE -8、DEBUG -4、INFO 0、WARNING 4、ERROR 8，例如 NOTICE 可以注册为 2、AUDIT 注册为 12，
+// 新级别通过 Logger.At 输出到单独的日志文件，名称和 rank 都不能与已有的级别重复
+func RegisterLevel(name string, rank int) (Level, error) {
+	if !levelNameRegexp.MatchString(name) {
+		return 0, fmt.Errorf("无效的日志级别名称：%q", name)
+	}
+	name = strings.ToUpper(name)
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	old := levelTable()
+	next := LevelError
+	for level, info := range old {
+		if info.name == name {
+			return 0, fmt.Errorf("日志级别 %s 已存在", name)
+		}
+		if info.rank == rank {
+			return 0, fmt.Errorf("rank %d 已被日志级别 %s 使用", rank, info.name)
+		}
+		if level > next {
+			next = level
+		}
+	}
+	next++
+	table := make(map[Level]levelInfo, len(old)+1)
+	for level, info := range old {
+		table[level] = info
+	}
+	table[next] = levelInfo{name: name, lower: strings.ToLower(name), rank: rank}
+	levels.Store(&table)
+	return next, nil
+}
+
+// lowerString 返回小写的级别名称
+func (l Level) lowerString() string {
+	return levelTable()[l].lower
+}
+
+// Levels 返回所有级别，包括 RegisterLevel 注册的级别，按 rank 从低到高排列
+func Levels() []Level {
+	table := levelTable()
+	all := make([]Level, 0, len(table))
+	for level := range table {
+		all = append(all, level)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return table[all[i]].rank < table[all[j]].rank
+	})
+	return all
+}
+
+// Rank 返回级别的排序值，未注册的级别低于 TRACE 时按 TRACE 处理，否则按 ERROR 处理
+func (l Level) Rank() int {
+	table := levelTable()
+	if info, ok := table[l]; ok {
+		return info.rank
+	}
+	if l < LevelTrace {
+		return table[LevelTrace].rank
+	}
+	return table[LevelError].rank
+}
+
+// lookupLevel 按名称查找级别，不区分大小写
+func lookupLevel(name string) (Level, bool) {
+	name = strings.ToUpper(name)
+	for level, info := range levelTable() {
+		if info.name == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// At 返回默认实例 level 级别的日志记录器
+func At(level Level) *logger {
+	return std.At(level)
+}
+
+// At 返回 level 级别的日志记录器，主要用于 RegisterLevel 注册的级别，第一次调用时为其创建日志文件，
+// 未注册的级别低于 TRACE 时返回 Trace，否则返回 Error 所使用的记录器
+func (l *Logger) At(level Level) *logger {
+	if lv := l.findLevel(level); lv != nil {
+		return lv
+	}
+	if _, ok := levelTable()[level]; !ok {
+		if level < LevelTrace {
+			return l.Trace
+		}
+		return &l.Error.logger
+	}
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	if lv := l.findLevel(level); lv != nil {
+		return lv
+	}
+	var custom []*logger
+	if old := l.custom.Load(); old != nil {
+		custom = append(custom, *old...)
+	}
+	lv := newLogger(l, level)
+	l.rotateLocked(lv)
+	custom = append(custom, lv)
+	l.custom.Store(&custom)
+	return lv
+}
+
+// findLevel 返回已创建的 level 级别的日志记录器，不存在时返回 nil
+func (l *Logger) findLevel(level Level) *logger {
+	switch level {
+	case LevelTrace:
+		return l.Trace
+	case LevelDebug:
+		return l.Debug
+	case LevelInfo:
+		return l.Info
+	case LevelWarning:
+		return l.Warning
+	case LevelError:
+		return &l.Error.logger
+	}
+	if custom := l.custom.Load(); custom != nil {
+		for _, lv := range *custom {
+			if lv.level == level {
+				return lv
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SetMaxMessageSize 设置默认实例日志内容的最大字节数
+func SetMaxMessageSize(n int) {
+	std.SetMaxMessageSize(n)
+}
+
+// SetMaxMessageSize 设置日志内容的最大字节数，超出的部分被截断并加上 "…(truncated N bytes)"，
+// 截断位置不会拆开多字节字符，n <= 0 时不限制
+func (l *Logger) SetMaxMessageSize(n int) {
+	l.maxMessageSize.Store(int64(n))
+}
+
+// SetEscapeNewlines 设置默认实例是否转义日志内容中的换行
+func SetEscapeNewlines(escape bool) {
+	std.SetEscapeNewlines(escape)
+}
+
+// SetEscapeNewlines 设置文本格式是否将日志内容中的换行转义为 \n，调用栈也会以 stack="..." 的形式写在同一行，
+// 保证每条日志只占一行，JSON 和 logfmt 格式总是转义换行
+func (l *Logger) SetEscapeNewlines(escape bool) {
+	l.escapeNewlines.Store(escape)
+}
+
+// truncateMessage 按 SetMaxMessageSize 截断 e.Message
+func (l *Logger) truncateMessage(e *Entry) {
+	max := int(l.maxMessageSize.Load())
+	if max <= 0 || len(e.Message) <= max {
+		return
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(e.Message[cut]) {
+		cut--
+	}
+	e.Message = e.Message[:cut] + "…(truncated " + strconv.Itoa(len(e.Message)-cut) + " bytes)"
+}
+
+var newlineEscaper = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`)
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+func encodeLogfmt(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	b.WriteString("ts=")
+	opts.writeTime(b, e.Time, defaultJSONTimeFormat)
+	b.WriteString(" level=")
+	b.WriteString(e.Level.lowerString())
+	if e.Module != "" {
+		b.WriteString(" module=")
+		writeLogfmtValue(b, e.Module)
+	}
+	b.WriteString(" msg=")
+	writeLogfmtValue(b, e.Message)
+	if e.Caller != "" {
+		b.WriteString(" caller=")
+		writeLogfmtValue(b, e.Caller)
+	}
+	writeLogfmtFields(b, opts.identity)
+	writeLogfmtFields(b, e.Fields)
+	if e.Err != nil {
+		b.WriteString(" error=")
+		writeLogfmtValue(b, e.Err.Error())
+		if causes := errorCauses(e.Err); len(causes) > 0 {
+			b.WriteString(" cause=")
+			writeLogfmtValue(b, causes[len(causes)-1].Error())
+		}
+	}
+	if e.Stack != "" {
+		b.WriteString(" stack=")
+		writeLogfmtValue(b, e.Stack)
+	}
+	b.WriteByte('\n')
+}
+
+func writeLogfmtFields(b *bytes.Buffer, fields []Field) {
+	var scratch [32]byte
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		switch v := fieldValue(f.Value).(type) {
+		case nil:
+		case string:
+			writeLogfmtValue(b, v)
+		case int:
+			b.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+		case int64:
+			b.Write(strconv.AppendInt(scratch[:0], v, 10))
+		case bool:
+			b.Write(strconv.AppendBool(scratch[:0], v))
+		default:
+			writeLogfmtValue(b, fmt.Sprint(v))
+		}
+	}
+}
+
+// writeLogfmtValue 写入 logfmt 的值，包含空白、引号、等号、控制字符或为空时加上引号
+func writeLogfmtValue(b *bytes.Buffer, s string) {
+	needQuote := s == ""
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' || r == '\\' || r == utf8.RuneError || r == 0x7f {
+			needQuote = true
+			break
+		}
+	}
+	if !needQuote {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(strconv.Quote(s))
+}
@@ -1,134 +1,849 @@
 package logger
 
 import (
+	"crypto/cipher"
+	"fmt"
 	"io"
-	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type logLevel int
+// Level 日志级别
+type Level int32
 
 const (
-	levelDebug logLevel = iota
-	levelInfo
-	levelWarning
-	levelError
+	// LevelTrace 比 Debug 更详细的级别，默认不输出
+	LevelTrace Level = iota - 1
+	LevelDebug
+	LevelInfo
+	LevelWarning
+	LevelError
 )
 
+// String 返回级别的名称，例如 DEBUG，未注册的级别返回空字符串
+func (l Level) String() string {
+	return levelTable()[l].name
+}
+
 var (
+	// std 是包级别 Trace、Debug、Info、Warning、Error 所属的默认实例
+	std     = newDefault()
+	Trace   = std.Trace
+	Debug   = std.Debug
+	Info    = std.Info
+	Warning = std.Warning
+	Error   = std.Error
+	Audit   = std.Audit
+	// now 是获取当前时间的唯一入口，所有与文件命名、轮转相关的时间都从这里读取
+	now   = time.Now
+	nowMu sync.RWMutex
+	// disabled 为 true 时所有日志调用直接返回
+	disabled atomic.Bool
+)
+
+// Logger 一组拥有独立目录、writer 和级别的日志记录器
+type Logger struct {
+	Trace   *logger
 	Debug   *logger
 	Info    *logger
 	Warning *logger
 	Error   *errorLogger
-	dateStr string
+	// Audit 写入带哈希链的审计日志
+	Audit *auditLogger
+
+	// rotateMu 保护日志文件的轮转与清理，二者可能在不同的 goroutine 中触发
+	rotateMu sync.Mutex
+	// mu 保护下面的配置
+	mu sync.Mutex
+	// period 是当前日志文件名中的时间部分，例如 2006-01-02 或按小时轮转时的 2006-01-02_15
+	period string
+	// periodStart、periodEnd 是当前轮转周期的边界（UnixNano），写入时无锁读取以判断是否需要轮转
+	periodStart atomic.Int64
+	periodEnd   atomic.Int64
+	// rotationInterval 是轮转间隔，0 表示按天轮转
+	rotationInterval time.Duration
+	// periodName 是 period 的副本，设置了轮转策略时写入时无锁读取以判断是否需要轮转
+	periodName atomic.Pointer[string]
+	// rotationPolicy 是 SetRotationPolicy 设置的轮转策略
+	rotationPolicy atomic.Pointer[RotationPolicy]
+	// fileHeader 是 SetFileHeader 设置的生成文件头的函数
+	fileHeader atomic.Pointer[func() string]
+	// dirPath 是日志目录，为空时使用当前目录
 	dirPath string
 	writers []io.Writer
-)
+	// levelWriters 是只接收某个级别日志的 writer
+	levelWriters map[Level][]io.Writer
+	// console 是 EnableConsole 开启的控制台输出，为 nil 时不输出到控制台，新创建的级别也会使用
+	console     *consoleOutput
+	maxBackups  int
+	maxAge      int
+	compress    bool
+	combined    bool
+	filePattern string
 
-func init() {
-	createLogger()
-	ticker := time.NewTicker(time.Second)
-	go func() {
-		for t := range ticker.C {
-			date := t.Format("2006-01-02")
-			if dateStr != date {
-				createLogger()
-			}
+	// minLevel 低于该级别的日志不会输出
+	minLevel    atomic.Int32
+	format      atomic.Int32
+	maxFileSize atomic.Int64
+	location    atomic.Pointer[time.Location]
+	timeFormat  atomic.Pointer[string]
+	// noFileOutput 为 true 时不写入日志文件
+	noFileOutput atomic.Bool
+	// stacktraceLevel 不为 nil 时该级别及以上的日志自动附加调用栈
+	stacktraceLevel atomic.Pointer[Level]
+	// maxMessageSize 大于 0 时截断超出该字节数的日志内容
+	maxMessageSize atomic.Int64
+	// escapeNewlines 为 true 时文本格式转义日志内容中的换行
+	escapeNewlines atomic.Bool
+	// minFreeDisk 是日志目录所在磁盘的最小剩余空间，diskLow 为 true 时低级别的日志不写入文件
+	minFreeDisk   atomic.Int64
+	diskLow       atomic.Bool
+	diskGuardOnce sync.Once
+	// fileMode、dirMode 是新建日志文件和目录的权限，为 0 时使用默认权限
+	fileMode atomic.Uint32
+	dirMode  atomic.Uint32
+	// fileLock 为 true 时写入日志文件期间持有文件锁
+	fileLock atomic.Bool
+	// syncEveryWrite 为 true 时每次写入日志文件后同步到磁盘
+	syncEveryWrite atomic.Bool
+	syncer         atomic.Pointer[syncer]
+	// bufferSize 大于 0 时日志文件带有该大小的写缓冲，flusher 定时将缓冲写入文件
+	bufferSize atomic.Int64
+	flusher    atomic.Pointer[syncer]
+	// identity 是 SetAppName、SetReportHostname、SetReportPID 设置的进程标识
+	identity atomic.Pointer[identity]
+	// reportCaller 为 true 时文本格式也会输出调用方的文件和行号
+	reportCaller atomic.Bool
+	// custom 是通过 At 创建的 RegisterLevel 注册的级别的日志记录器，在 rotateMu 下追加
+	custom atomic.Pointer[[]*logger]
+	// shards 是 ForKey 的各个键在当前周期的日志文件，轮转时整体替换，写入时持有 shardMu 的读锁
+	shardMu sync.RWMutex
+	shards  *shardSet
+	// shardDirs 是 ForKey 和 ForTenant 在本进程中写入过的子目录（相对于日志目录），由 mu 保护
+	shardDirs map[string]bool
+
+	filters atomic.Pointer[[]Filter]
+	hooks   atomic.Pointer[[]Hook]
+	// rotateCallbacks 是 OnRotate 添加的回调，rotateEvents 是等待调用回调的切换，队列非空时有一个 goroutine 在处理
+	rotateCallbacks atomic.Pointer[[]func(oldPath, newPath string)]
+	// compressCallbacks 是 OnCompress 添加的回调，与 rotateCallbacks 共用 rotateEvents 队列
+	compressCallbacks atomic.Pointer[[]func(path, gzPath string)]
+	rotateEventsMu    sync.Mutex
+	rotateEvents      []rotateEvent
+	sampler           atomic.Pointer[sampler]
+	dedup             atomic.Pointer[deduper]
+	ring              atomic.Pointer[ringBuffer]
+	followers         atomic.Pointer[[]*follower]
+	encryption        atomic.Pointer[cipher.AEAD]
+	checksums         atomic.Bool
+	catalog           atomic.Pointer[MessageCatalog]
+	errorHandler      atomic.Pointer[ErrorHandler]
+	outputFunc        atomic.Pointer[func(e Entry)]
+	encoder           atomic.Pointer[Encoder]
+	shadow            atomic.Pointer[shadowWriter]
+	fingerprint       atomic.Bool
+	goroutineID       atomic.Bool
+	internal          atomic.Pointer[internalLogger]
+	codec             atomic.Pointer[Codec]
+	accessLevel       atomic.Pointer[Level]
+	streamCompress    atomic.Bool
+	clock             atomic.Pointer[func() time.Time]
+	// moduleLevels 是 SetModuleLevel 设置的各模块的最低级别，修改时整体替换
+	moduleLevels atomic.Pointer[map[string]Level]
+	metrics      metrics
+
+	// asyncMu 保护 async 的切换，发送日志时持有读锁
+	asyncMu        sync.RWMutex
+	async          *asyncQueue
+	overflowPolicy atomic.Int32
+
+	// stop 在 Close 时关闭，用于停止后台 goroutine 和写入时的轮转，tasks 记录所有后台 goroutine
+	stop      chan struct{}
+	closeOnce sync.Once
+	tasks     sync.WaitGroup
+}
+
+// Default 返回包级别函数所使用的默认实例
+func Default() *Logger {
+	return std
+}
+
+// Option 用于 New 的配置项
+type Option func(*Logger)
+
+// WithDir 设置日志目录
+func WithDir(path string) Option {
+	return func(l *Logger) {
+		// New 没有返回错误，目录不可用时仍使用该目录，在第一次打开日志文件时报错，而不是写入当前目录
+		l.dirPath, _ = makeDir(path, l.getDirMode())
+	}
+}
+
+// WithWriters 设置附加的 writer
+func WithWriters(writer ...io.Writer) Option {
+	return func(l *Logger) {
+		l.writers = append(l.writers, lockWriters(writer)...)
+	}
+}
+
+// WithLevel 设置最低输出级别
+func WithLevel(level Level) Option {
+	return func(l *Logger) {
+		l.minLevel.Store(int32(level))
+	}
+}
+
+// WithFormat 设置输出格式
+func WithFormat(f Format) Option {
+	return func(l *Logger) {
+		l.format.Store(int32(f))
+	}
+}
+
+// New 创建一个独立的日志实例，写入时发现进入新的轮转周期会先切换日志文件
+func New(opts ...Option) *Logger {
+	l := &Logger{}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.Trace = newLogger(l, LevelTrace)
+	l.Debug = newLogger(l, LevelDebug)
+	l.Info = newLogger(l, LevelInfo)
+	l.Warning = newLogger(l, LevelWarning)
+	l.Error = &errorLogger{logger: logger{owner: l, level: LevelError, rank: LevelError.Rank()}}
+	l.Audit = newAuditLogger(l)
+	l.stop = make(chan struct{})
+	l.rotate()
+	return l
+}
+
+// rotate 按当前日期和目录切换到新的日志文件，并清理过期的历史文件
+func (l *Logger) rotate() {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.rotateLocked()
+}
+
+// rotateLocked 是 rotate 的实现，extra 是尚未加入 levels 的新级别，调用方需持有 rotateMu
+func (l *Logger) rotateLocked(extra ...*logger) {
+	levels := append(l.levels(), extra...)
+	l.mu.Lock()
+	l.setPeriod(l.now())
+	// 合并输出时各级别的文件名相同，共用同一个 fileSink
+	sinks := make(map[string]*fileSink)
+	levelSinks := make(map[Level]*fileSink)
+	for _, lv := range levels {
+		base, ext := l.fileBase(l.period, lv.level.lowerString())
+		if sinks[base+ext] == nil {
+			sinks[base+ext] = newFileSink(l, filepath.Join(l.dirPath, base), ext)
 		}
-	}()
+		levelSinks[lv.level] = sinks[base+ext]
+	}
+	l.mu.Unlock()
+	// 记录各级别正在写入的文件，切换之后通知 OnRotate 的回调，合并输出时同一个文件只通知一次
+	active := make(map[*fileSink]*fileSink)
+	for _, lv := range levels {
+		lv.mu.RLock()
+		if lv.sink != nil {
+			active[lv.sink] = levelSinks[lv.level]
+		}
+		lv.mu.RUnlock()
+	}
+	for _, lv := range levels {
+		lv.reset(levelSinks[lv.level])
+		lv.quota.reset()
+	}
+	for old, sink := range active {
+		if name := old.activeName(); name != "" && old != sink {
+			l.notifyRotate(name, sink.pendingName())
+		}
+	}
+	l.rotateShards()
+	l.removeBackups()
+	l.goBackground(l.compressBackups)
+}
+
+// levels 返回所有已创建的级别的日志记录器，按 rank 从低到高排列
+func (l *Logger) levels() []*logger {
+	builtin := []*logger{l.Trace, l.Debug, l.Info, l.Warning, &l.Error.logger}
+	custom := l.custom.Load()
+	if custom == nil {
+		return builtin
+	}
+	all := append(builtin, *custom...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].rank < all[j].rank
+	})
+	return all
 }
 
-func createLogger() {
-	dateStr = time.Now().Format("2006-01-02")
-	Debug = newLogger(levelDebug, dirPath+dateStr+".debug.log")
-	Info = newLogger(levelInfo, dirPath+dateStr+".info.log")
-	Warning = newLogger(levelWarning, dirPath+dateStr+".warning.log")
-	Error = newErrorLogger(levelError, dirPath+dateStr+".error.log")
+// sinks 返回各级别以及 ForKey 的各个键当前使用的日志文件，合并输出时每个键只有一个
+func (l *Logger) sinks() []*fileSink {
+	var sinks []*fileSink
+	seen := make(map[*fileSink]bool)
+	for _, lv := range l.levels() {
+		lv.mu.Lock()
+		sink := lv.sink
+		lv.mu.Unlock()
+		if sink != nil && !seen[sink] {
+			seen[sink] = true
+			sinks = append(sinks, sink)
+		}
+	}
+	return append(sinks, l.shardSinks()...)
 }
 
-func AppendWriter(writer ...io.Writer) {
-	writers = append(writers, writer...)
+// SetCombinedOutput 设置是否将所有级别的日志写入同一个文件，例如 2006-01-02.log
+func (l *Logger) SetCombinedOutput(combined bool) {
+	l.mu.Lock()
+	l.combined = combined
+	l.mu.Unlock()
+	l.rotate()
 }
 
-func SetDir(path string) {
+// SetDir 设置日志目录，已打开的日志文件会被关闭，之后的日志写入新目录，
+// 目录无法创建或不可写时保持原来的目录并将错误交给 SetErrorHandler 设置的回调，需要处理错误时使用 SetDirE
+func (l *Logger) SetDir(path string) {
+	if err := l.SetDirE(path); err != nil {
+		l.reportError(nil, err)
+	}
+}
+
+// SetDirE 与 SetDir 相同，但在目录无法创建或不可写时返回错误
+func (l *Logger) SetDirE(path string) error {
 	if path == "" {
-		return
+		return nil
 	}
-	if path[len(path)-1:] != "/" {
-		path += "/"
+	path, err := makeDir(path, l.getDirMode())
+	if err != nil {
+		return err
 	}
-	_ = os.Mkdir(path, os.ModePerm)
-	dirPath = path
-	createLogger()
+	l.mu.Lock()
+	l.dirPath = path
+	l.mu.Unlock()
+	l.rotate()
+	return nil
+}
+
+// AppendWriter 添加接收所有级别日志的 writer，已打开的日志文件会立即开始向其写入，返回添加后接收所有级别日志的 writer，
+// nil 和已经添加过的 writer 会被跳过并返回 ErrNilWriter 或 ErrDuplicateWriter，其余的 writer 仍会添加
+func (l *Logger) AppendWriter(writer ...io.Writer) ([]io.Writer, error) {
+	l.mu.Lock()
+	added, err := l.validWriters(writer, nil)
+	l.writers = append(l.writers, lockWriters(added)...)
+	current := unwrapWriters(l.writers)
+	l.mu.Unlock()
+	l.refreshWriters()
+	return current, err
 }
 
-func newLogger(level logLevel, fileName string) *logger {
+// AppendWriterFor 添加只接收 level 级别日志的 writer，返回添加后只接收 level 级别日志的 writer，
+// 参数的检查与 AppendWriter 相同
+func (l *Logger) AppendWriterFor(level Level, writer ...io.Writer) ([]io.Writer, error) {
+	l.mu.Lock()
+	if l.levelWriters == nil {
+		l.levelWriters = make(map[Level][]io.Writer)
+	}
+	added, err := l.validWriters(writer, &level)
+	l.levelWriters[level] = append(l.levelWriters[level], lockWriters(added)...)
+	current := unwrapWriters(l.levelWriters[level])
+	l.mu.Unlock()
+	l.refreshWriters()
+	return current, err
+}
+
+// refreshWriters 在 writer 变化后重新组合已打开的各级别的输出
+func (l *Logger) refreshWriters() {
+	for _, lv := range l.levels() {
+		lv.mu.Lock()
+		lv.out = l.newMultiWriter(l.outputs(lv.level, lv.sink))
+		lv.mu.Unlock()
+	}
+}
+
+// outputs 返回 level 级别的日志需要写入的 writer 和日志文件，关闭文件输出或磁盘空间不足时不包含日志文件
+func (l *Logger) outputs(level Level, sink *fileSink) []io.Writer {
+	w := l.writersFor(level)
+	if l.noFileOutput.Load() || (l.diskLow.Load() && level.Rank() < LevelWarning.Rank()) {
+		return w
+	}
+	return append(w, sink)
+}
+
+// DisableFileOutput 关闭写入日志文件，只输出到附加的 writer，已打开的日志文件会被关闭
+func (l *Logger) DisableFileOutput() {
+	l.noFileOutput.Store(true)
+	l.refreshWriters()
+	for _, sink := range l.sinks() {
+		_ = sink.close()
+	}
+}
+
+// EnableFileOutput 恢复写入日志文件
+func (l *Logger) EnableFileOutput() {
+	l.noFileOutput.Store(false)
+	l.refreshWriters()
+}
+
+// writersFor 返回 level 级别的日志需要写入的所有 writer
+func (l *Logger) writersFor(level Level) []io.Writer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := make([]io.Writer, 0, len(l.writers)+len(l.levelWriters[level])+1)
+	w = append(w, l.writers...)
+	w = append(w, l.levelWriters[level]...)
+	if l.console != nil {
+		w = append(w, l.console.writerFor(level))
+	}
+	return w
+}
+
+// SetLevel 设置最低输出级别，低于该级别的日志调用不做任何处理
+func (l *Logger) SetLevel(level Level) {
+	l.minLevel.Store(int32(level))
+}
+
+// SetFormat 设置日志的输出格式
+func (l *Logger) SetFormat(f Format) {
+	l.format.Store(int32(f))
+}
+
+// GetLevel 返回当前的最低输出级别
+func (l *Logger) GetLevel() Level {
+	return Level(l.minLevel.Load())
+}
+
+func (l *Logger) getFormat() Format {
+	return Format(l.format.Load())
+}
+
+// makeDir 以 mode 权限创建日志目录及其上级目录并检查是否可写，出错时也返回清理后的目录路径
+func makeDir(path string, mode os.FileMode) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	path = filepath.Clean(path)
+	if err := os.MkdirAll(path, mode); err != nil {
+		return path, fmt.Errorf("创建日志目录失败：%w", err)
+	}
+	f, err := os.CreateTemp(path, ".go_logger-*")
+	if err != nil {
+		return path, fmt.Errorf("日志目录不可写：%w", err)
+	}
+	f.Close()
+	_ = os.Remove(f.Name())
+	return path, nil
+}
+
+// SetClockForTesting 替换所有实例获取当前时间的函数，仅用于测试跨天轮转，传入 nil 恢复为 time.Now，
+// 只需要修改一个实例时使用 Logger.SetClock
+func SetClockForTesting(clock func() time.Time) {
+	nowMu.Lock()
+	defer nowMu.Unlock()
+	if clock == nil {
+		clock = time.Now
+	}
+	now = clock
+}
+
+func currentTime() time.Time {
+	nowMu.RLock()
+	defer nowMu.RUnlock()
+	return now()
+}
+
+// SetCombinedOutput 设置默认实例是否将所有级别的日志写入同一个文件
+func SetCombinedOutput(combined bool) {
+	std.SetCombinedOutput(combined)
+}
+
+// SetReportCaller 设置默认实例是否输出调用方的文件和行号
+func SetReportCaller(report bool) {
+	std.SetReportCaller(report)
+}
+
+// SetReportCaller 设置是否在每条日志中输出调用方的文件和行号，JSON 等结构化格式总是包含 caller 字段
+func (l *Logger) SetReportCaller(report bool) {
+	l.reportCaller.Store(report)
+}
+
+// needCaller 判断当前的配置是否需要输出调用方
+func (l *Logger) needCaller() bool {
+	return l.reportCaller.Load() || l.structured()
+}
+
+// SetTimezone 设置默认实例使用的时区
+func SetTimezone(name string) error {
+	return std.SetTimezone(name)
+}
+
+// SetTimezone 设置日志时间戳和文件名日期使用的时区，例如 Asia/Shanghai
+func (l *Logger) SetTimezone(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	l.location.Store(loc)
+	l.rotateIfChanged()
+	return nil
+}
+
+// now 返回该实例所在时区的当前时间，设置了 SetClock 时使用该实例的时钟
+func (l *Logger) now() time.Time {
+	var t time.Time
+	if clock := l.clock.Load(); clock != nil {
+		t = (*clock)()
+	} else {
+		t = currentTime()
+	}
+	if loc := l.location.Load(); loc != nil {
+		t = t.In(loc)
+	}
+	return t
+}
+
+// SetLevel 设置默认实例的最低输出级别
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
+
+// GetLevel 返回默认实例的最低输出级别
+func GetLevel() Level {
+	return std.GetLevel()
+}
+
+// SetFormat 设置默认实例的输出格式
+func SetFormat(f Format) {
+	std.SetFormat(f)
+}
+
+// Disable 关闭所有日志输出，包括审计日志，Fatalln/Fatalf 仍然会退出进程，Panicln/Panicf 仍然会 panic
+func Disable() {
+	disabled.Store(true)
+}
+
+// Enable 恢复日志输出
+func Enable() {
+	disabled.Store(false)
+}
+
+func AppendWriter(writer ...io.Writer) ([]io.Writer, error) {
+	return std.AppendWriter(writer...)
+}
+
+// DisableFileOutput 关闭默认实例的日志文件输出
+func DisableFileOutput() {
+	std.DisableFileOutput()
+}
+
+// EnableFileOutput 恢复默认实例的日志文件输出
+func EnableFileOutput() {
+	std.EnableFileOutput()
+}
+
+// AppendWriterFor 为默认实例添加只接收 level 级别日志的 writer
+func AppendWriterFor(level Level, writer ...io.Writer) ([]io.Writer, error) {
+	return std.AppendWriterFor(level, writer...)
+}
+
+func SetDir(path string) {
+	std.SetDir(path)
+}
+
+// SetDirE 设置默认实例的日志目录，目录无法创建或不可写时返回错误
+func SetDirE(path string) error {
+	return std.SetDirE(path)
+}
+
+func newLogger(owner *Logger, level Level) *logger {
 	return &logger{
-		logger:   nil,
-		fileName: fileName,
-		level:    level,
+		owner: owner,
+		level: level,
+		rank:  level.Rank(),
 	}
 }
 
 type logger struct {
-	logger   *log.Logger
-	fileName string
-	level    logLevel
+	owner *Logger
+	// mu 保护 out 和 sink 的切换，写入时只持有读锁，多个 goroutine 可以同时写入，
+	// 由各个 writer 自己保证并发安全
+	mu sync.RWMutex
+	// out 由附加的 writer 和 sink 组成
+	out   *multiWriter
+	sink  *fileSink
+	level Level
+	// rank 是 level 的排序值，用于按最低级别过滤
+	rank int
+	// lines 是成功写入的日志条数
+	lines atomic.Uint64
+	// quota 是 SetDailyQuota 设置的配额
+	quota levelQuota
 }
 
-func (l *logger) Println(v ...interface{}) {
-	if l.logger == nil {
-		file, err := os.OpenFile(l.fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			log.Fatalln("打开日志文件失败：", err)
+// Enabled 判断该级别的日志当前是否需要输出，用于在构造开销较大的日志内容之前判断：
+//
+//	if logger.Debug.Enabled() {
+//		logger.Debug.Println(dump(state))
+//	}
+func (l *logger) Enabled() bool {
+	return l.enabled()
+}
+
+// enabled 判断该级别的日志当前是否需要输出
+func (l *logger) enabled() bool {
+	return l.enabledFor("")
+}
+
+// enabledFor 判断该级别的日志在模块 module 中是否需要输出，模块设置了级别时优先使用模块的级别
+func (l *logger) enabledFor(module string) bool {
+	if disabled.Load() {
+		return false
+	}
+	min, ok := l.owner.moduleLevel(module)
+	if !ok {
+		min = Level(l.owner.minLevel.Load())
+	}
+	return l.rank >= min.Rank()
+}
+
+// reset 切换到新的日志文件，旧文件会被关闭，新文件在下一次写入时打开
+func (l *logger) reset(sink *fileSink) {
+	outputs := l.owner.outputs(l.level, sink)
+	l.mu.Lock()
+	old := l.sink
+	l.sink = sink
+	l.out = l.owner.newMultiWriter(outputs)
+	l.mu.Unlock()
+	if old != nil && old != sink {
+		_ = old.close()
+	}
+}
+
+// goBackground 启动一个后台 goroutine，Close 时会等待其结束
+func (l *Logger) goBackground(f func()) {
+	l.tasks.Add(1)
+	go func() {
+		defer l.tasks.Done()
+		f()
+	}()
+}
+
+// Sync 写完异步队列中的日志，刷新附加的 writer 中的缓冲并将日志文件同步到磁盘
+func (l *Logger) Sync() error {
+	l.Flush()
+	l.mu.Lock()
+	writers := l.writers[:len(l.writers):len(l.writers)]
+	for _, w := range l.levelWriters {
+		writers = append(writers, w...)
+	}
+	l.mu.Unlock()
+	var firstErr error
+	for _, w := range writers {
+		var err error
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			err = f.Flush()
+		case interface{ Sync() error }:
+			err = f.Sync()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
-		w := append(writers, file)
-		l.logger = log.New(io.MultiWriter(w...), "", log.Ldate|log.Lmicroseconds)
 	}
-	switch l.level {
-	case levelDebug:
-		v = append([]interface{}{"DEBUG"}, v...)
-	case levelInfo:
-		v = append([]interface{}{"INFO"}, v...)
-	case levelWarning:
-		v = append([]interface{}{"WARNING"}, v...)
-	case levelError:
-		v = append([]interface{}{"ERROR"}, v...)
+	for _, sink := range l.sinks() {
+		if err := sink.sync(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	l.logger.Println(v...)
+	return firstErr
+}
+
+// Close 写完异步队列中的日志，停止所有后台 goroutine，同步并关闭日志文件，
+// 之后的写入会重新打开文件，但不再按天轮转
+func (l *Logger) Close() error {
+	l.SetDedup(0)
+	for _, p := range []*atomic.Pointer[syncer]{&l.syncer, &l.flusher} {
+		if s := p.Swap(nil); s != nil {
+			s.stop()
+		}
+	}
+	l.SetAsync(0)
+	l.closeOnce.Do(func() {
+		close(l.stop)
+	})
+	l.tasks.Wait()
+	l.reportDroppedNow()
+	err := l.Sync()
+	for _, sink := range l.sinks() {
+		if closeErr := sink.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := l.Audit.state.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (l *logger) Println(v ...interface{}) {
+	l.println(nil, v)
 }
 
 func (l *logger) Printf(format string, v ...interface{}) {
-	if l.logger == nil {
-		file, err := os.OpenFile(l.fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			log.Fatalln("打开日志文件失败：", err)
+	l.printf(nil, format, v)
+}
+
+// Fatalln 输出日志并在刷新所有 writer 后以状态码 1 退出进程
+func (l *logger) Fatalln(v ...interface{}) {
+	l.Println(v...)
+	_ = l.owner.Sync()
+	os.Exit(1)
+}
+
+// Fatalf 输出日志并在刷新所有 writer 后以状态码 1 退出进程
+func (l *logger) Fatalf(format string, v ...interface{}) {
+	l.Printf(format, v...)
+	_ = l.owner.Sync()
+	os.Exit(1)
+}
+
+// Panicln 输出日志后以日志内容 panic
+func (l *logger) Panicln(v ...interface{}) {
+	l.Println(v...)
+	panic(sprintln(v))
+}
+
+// Panicf 输出日志后以日志内容 panic
+func (l *logger) Panicf(format string, v ...interface{}) {
+	l.Printf(format, v...)
+	panic(fmt.Sprintf(format, v...))
+}
+
+// println 输出一行日志，o 携带 With、WithContext、Named 附加的信息，为 nil 时表示没有附加信息
+func (l *logger) println(o *fieldLogger, v []interface{}) {
+	if !l.enabledFor(o.moduleName()) {
+		return
+	}
+	msg, err := l.sprintln(v, o.expandsErr())
+	l.output(o, msg, err, "")
+}
+
+// sprintln 按 Println 的规则生成日志内容，expandErr 为 true 时展开 error，JSON 格式下最后一个 error 单独返回
+func (l *logger) sprintln(v []interface{}, expandErr bool) (string, error) {
+	v = evalLazy(v)
+	var err error
+	if expandErr {
+		if l.owner.structured() {
+			v, err = popError(v)
+		} else {
+			v = expandErrorln(v)
 		}
-		w := append(writers, file)
-		l.logger = log.New(io.MultiWriter(w...), "", log.Ldate|log.Lmicroseconds)
 	}
-	switch l.level {
-	case levelDebug:
-		format = "DEBUG " + format + "\n"
-	case levelInfo:
-		format = "INFO " + format + "\n"
-	case levelWarning:
-		format = "WARNING " + format + "\n"
-	case levelError:
-		format = "ERROR " + format + "\n"
+	return sprintln(v), err
+}
+
+func (l *logger) printf(o *fieldLogger, format string, v []interface{}) {
+	if !l.enabledFor(o.moduleName()) {
+		return
+	}
+	v = evalLazy(v)
+	var err error
+	if o.expandsErr() {
+		if l.owner.structured() {
+			v, err = replaceError(v)
+		} else {
+			v = expandErrorf(v)
+		}
+	}
+	l.outputTemplate(o, fmt.Sprintf(format, v...), format, err, "")
+}
+
+// output 按当前的输出格式编码一条日志并写入文件及附加的 writer，err 仅用于结构化输出，
+// stackTrace 为空且低于 SetStacktraceLevel 设置的级别时不输出调用栈
+func (l *logger) output(o *fieldLogger, msg string, err error, stackTrace string) {
+	l.outputTemplate(o, msg, "", err, stackTrace)
+}
+
+// outputTemplate 与 output 相同，template 是 Printf 的格式字符串，用于计算 SetFingerprint 的指纹
+func (l *logger) outputTemplate(o *fieldLogger, msg, template string, err error, stackTrace string) {
+	if stackTrace == "" && l.owner.needStack(l.rank) {
+		stackTrace = stack()
+	}
+	e := &Entry{
+		Time:    l.owner.now(),
+		Level:   l.level,
+		Message: msg,
+		Err:     err,
+		Stack:   stackTrace,
 	}
-	l.logger.Printf(format, v...)
+	if o != nil {
+		e.Module = o.module
+		e.Fields = o.fields
+		e.Context = o.ctx
+		e.shard = o.shard
+	}
+	if l.owner.needCaller() {
+		e.Caller = caller()
+	}
+	l.owner.decorate(e, template)
+	l.log(e)
+}
+
+// decorate 为日志添加 SetFingerprint 和 SetGoroutineID 的字段，单条、批量和 slog 的输出都经过这一步
+func (l *Logger) decorate(e *Entry, template string) {
+	l.addFingerprint(e, template)
+	l.addGoroutineID(e)
 }
 
-func newErrorLogger(level logLevel, fileName string) *errorLogger {
-	return &errorLogger{
-		logger{
-			logger:   nil,
-			fileName: fileName,
-			level:    level,
-		},
+// log 编码一条日志并写入，异步模式下放入队列
+func (l *logger) log(e *Entry) {
+	l.owner.truncateMessage(e)
+	if !l.owner.applyFilters(e) || !l.owner.sampled(e) || l.owner.deduplicated(l, e) {
+		return
+	}
+	l.emit(e)
+}
+
+// emit 调用 Hook 后编码并写入一条日志，不再经过过滤、采样和重复合并
+func (l *logger) emit(e *Entry) {
+	l.owner.rotateOnWrite(e.Time)
+	l.owner.fireHooks(e)
+	l.owner.callOutputFunc(e)
+	l.owner.notifyFollowers(e)
+	b := getBuffer()
+	defer putBuffer(b)
+	opts := l.owner.encodeOptions()
+	encodeEntry(b, e, opts)
+	l.owner.writeShadow(e, opts)
+	l.owner.recordRecent(b.Bytes())
+	if l.owner.enqueue(l, e.shard, b.Bytes(), nil) {
+		return
+	}
+	l.write(e.shard, b.Bytes(), nil)
+}
+
+// write 将编码后的日志写入文件及附加的 writer，ends 不为 nil 时 line 是 PrintBatch 的多条日志，
+// shard 不为空时写入 ForKey 该键的日志文件，超出 SetDailyQuota 的配额时丢弃
+func (l *logger) write(shard string, line []byte, ends []int) {
+	if !l.withinQuota(len(line)) {
+		return
 	}
+	if shard != "" {
+		l.owner.writeShard(l, shard, line, ends)
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	n, err := l.out.writeBatch(line, ends)
+	count := 1
+	if ends != nil {
+		count = len(ends)
+	}
+	l.owner.metrics.recordWrite(&l.lines, count, n, err)
 }
 
 type errorLogger struct {
@@ -136,19 +851,45 @@ type errorLogger struct {
 }
 
 func (l *errorLogger) Println(v ...interface{}) {
-	l.logger.Println(v...)
+	l.println(&fieldLogger{logger: &l.logger, expandErr: true}, v)
 }
 
 func (l *errorLogger) Printf(format string, v ...interface{}) {
-	l.logger.Printf(format, v...)
+	l.printf(&fieldLogger{logger: &l.logger, expandErr: true}, format, v)
+}
+
+func (l *errorLogger) With(kv ...interface{}) *fieldLogger {
+	return (&fieldLogger{logger: &l.logger, expandErr: true}).With(kv...)
+}
+
+func (l *errorLogger) Fields(fields map[string]interface{}) *fieldLogger {
+	return (&fieldLogger{logger: &l.logger, expandErr: true}).Fields(fields)
 }
 
 func (l *errorLogger) Fatalln(v ...interface{}) {
-	l.logger.Println(v...)
+	l.Println(v...)
+	_ = l.owner.Sync()
 	os.Exit(1)
 }
 
 func (l *errorLogger) Fatalf(format string, v ...interface{}) {
-	l.logger.Printf(format, v...)
+	l.Printf(format, v...)
+	_ = l.owner.Sync()
 	os.Exit(1)
 }
+
+func (l *errorLogger) Panicln(v ...interface{}) {
+	l.Println(v...)
+	panic(sprintln(v))
+}
+
+func (l *errorLogger) Panicf(format string, v ...interface{}) {
+	l.Printf(format, v...)
+	panic(fmt.Sprintf(format, v...))
+}
+
+// sprintln 与 fmt.Sprintln 相同，但不带结尾的换行符
+func sprintln(v []interface{}) string {
+	msg := fmt.Sprintln(v...)
+	return msg[:len(msg)-1]
+}
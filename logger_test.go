@@ -0,0 +1,301 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// entryRecorder 是记录收到的日志的 Hook
+type entryRecorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (r *entryRecorder) Fire(e *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *e)
+}
+
+// list 返回收到的所有日志
+func (r *entryRecorder) list() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// last 返回最后一条日志
+func (r *entryRecorder) last(t *testing.T) Entry {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		t.Fatal("没有收到日志")
+	}
+	return r.entries[len(r.entries)-1]
+}
+
+// fieldValueOf 返回日志中 key 字段的值
+func fieldValueOf(e Entry, key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// syncMarker 在 Flush 时向标准输出写入 synced，用于确认退出前执行了 Sync
+type syncMarker struct{}
+
+func (syncMarker) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func (syncMarker) Flush() error {
+	_, err := fmt.Fprintln(os.Stdout, "synced")
+	return err
+}
+
+// TestFatalExitsWhenDisabled 在子进程中调用 Disable 之后的 Fatalln/Fatalf，确认没有输出日志，仍然执行了 Sync 并以状态码 1 退出
+func TestFatalExitsWhenDisabled(t *testing.T) {
+	if mode := os.Getenv("GO_LOGGER_TEST_FATAL"); mode != "" {
+		SetDir(os.Getenv("GO_LOGGER_TEST_DIR"))
+		_, _ = AppendWriter(syncMarker{})
+		Disable()
+		if mode == "f" {
+			Error.Fatalf("fatal %d", 1)
+		}
+		Error.Fatalln("fatal 1")
+		return
+	}
+	for _, mode := range []string{"ln", "f"} {
+		t.Run(mode, func(t *testing.T) {
+			dir := t.TempDir()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatalExitsWhenDisabled$")
+			cmd.Env = append(os.Environ(), "GO_LOGGER_TEST_FATAL="+mode, "GO_LOGGER_TEST_DIR="+dir)
+			out, err := cmd.Output()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				t.Fatalf("子进程应当以状态码 1 退出，得到 %v，输出：%s", err, out)
+			}
+			if !strings.Contains(string(out), "synced") {
+				t.Errorf("退出前没有执行 Sync，输出：%s", out)
+			}
+			if strings.Contains(string(out), "fatal 1") {
+				t.Errorf("关闭输出后不应当写入日志，输出：%s", out)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("关闭输出后不应当创建日志文件，得到 %d 个文件", len(entries))
+			}
+		})
+	}
+}
+
+func TestMakeDir(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	readOnly := filepath.Join(root, "readonly")
+	if err := os.Mkdir(readOnly, 0o555); err != nil {
+		t.Fatal(err)
+	}
+	sep := string(filepath.Separator)
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+		// skip 返回非空字符串时跳过该用例
+		skip func() string
+	}{
+		{name: "嵌套目录", path: filepath.Join(root, "a", "b", "c"), want: filepath.Join(root, "a", "b", "c")},
+		{name: "末尾的分隔符", path: filepath.Join(root, "trailing") + sep, want: filepath.Join(root, "trailing")},
+		{name: "相对的路径段", path: filepath.Join(root, "x") + sep + ".." + sep + "y", want: filepath.Join(root, "y")},
+		{name: "重复的分隔符", path: root + sep + sep + "double" + sep + sep + "dir", want: filepath.Join(root, "double", "dir")},
+		{
+			name: "Windows 路径",
+			path: root + `\win\logs\app`,
+			want: filepath.Join(root, "win", "logs", "app"),
+			skip: func() string {
+				if runtime.GOOS != "windows" {
+					return "反斜杠只在 Windows 上是路径分隔符"
+				}
+				return ""
+			},
+		},
+		{name: "上级是文件", path: filepath.Join(file, "sub"), wantErr: "创建日志目录失败"},
+		{
+			name:    "目录不可写",
+			path:    readOnly,
+			wantErr: "日志目录不可写",
+			skip: func() string {
+				if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+					return "Windows 和 root 用户不受目录权限限制"
+				}
+				return ""
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.skip != nil {
+				if reason := tt.skip(); reason != "" {
+					t.Skip(reason)
+				}
+			}
+			got, err := makeDir(tt.path, 0o755)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("makeDir(%q) 的错误为 %v，应当包含 %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("makeDir(%q) 失败：%v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("makeDir(%q) = %q，应当为 %q", tt.path, got, tt.want)
+			}
+			if info, err := os.Stat(got); err != nil || !info.IsDir() {
+				t.Errorf("目录 %q 没有被创建：%v", got, err)
+			}
+		})
+	}
+}
+
+func TestSetDirE(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		path    string
+		wantDir string
+		wantErr bool
+	}{
+		{name: "嵌套目录", path: filepath.Join(root, "nested", "app"), wantDir: filepath.Join(root, "nested", "app")},
+		{name: "相对的路径段", path: filepath.Join(root, "nested") + string(filepath.Separator) + ".." + string(filepath.Separator) + "other", wantDir: filepath.Join(root, "other")},
+		{name: "无法创建时保持原来的目录", path: filepath.Join(file, "sub"), wantDir: filepath.Join(root, "initial"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(WithDir(filepath.Join(root, "initial")))
+			defer l.Close()
+			err := l.SetDirE(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetDirE(%q) 的错误为 %v，wantErr 为 %v", tt.path, err, tt.wantErr)
+			}
+			l.Info.Println("hello")
+			if err := l.Sync(); err != nil {
+				t.Fatal(err)
+			}
+			name := filepath.Join(tt.wantDir, l.now().Format("2006-01-02")+".info.log")
+			if _, err := os.Stat(name); err != nil {
+				t.Errorf("日志应当写入 %s：%v", name, err)
+			}
+		})
+	}
+}
+
+// fakeClock 是可以手动调整的时钟
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// assertLogFiles 检查 dir 中存在 names 中的所有日志文件
+func assertLogFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("应当存在日志文件 %s：%v", name, err)
+		}
+	}
+}
+
+func TestSetClockForTestingRotatesAtMidnight(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 9, 23, 59, 59, 0, time.Local)}
+	SetClockForTesting(clock.now)
+	defer SetClockForTesting(nil)
+	dir := t.TempDir()
+	l := New(WithDir(dir))
+	defer l.Close()
+	l.Info.Println("before midnight")
+	clock.set(time.Date(2024, 3, 10, 0, 0, 1, 0, time.Local))
+	l.Info.Println("after midnight")
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	assertLogFiles(t, dir, "2024-03-09.info.log", "2024-03-10.info.log")
+}
+
+func TestSetClockRotatesAtMidnight(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 3, 9, 23, 59, 59, 0, time.Local)}
+	dir := t.TempDir()
+	l := New(WithDir(dir), WithClock(clock.now))
+	defer l.Close()
+	l.Info.Println("before midnight")
+	clock.set(time.Date(2024, 3, 10, 0, 0, 1, 0, time.Local))
+	l.Info.Println("after midnight")
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	assertLogFiles(t, dir, "2024-03-09.info.log", "2024-03-10.info.log")
+}
+
+func TestSetClockIsPerInstance(t *testing.T) {
+	first := &fakeClock{t: time.Date(2024, 3, 9, 12, 0, 0, 0, time.Local)}
+	second := &fakeClock{t: time.Date(2025, 7, 1, 12, 0, 0, 0, time.Local)}
+	firstDir, secondDir, plainDir := t.TempDir(), t.TempDir(), t.TempDir()
+	a := New(WithDir(firstDir), WithClock(first.now))
+	defer a.Close()
+	b := New(WithDir(secondDir))
+	defer b.Close()
+	b.SetClock(second.now)
+	c := New(WithDir(plainDir))
+	defer c.Close()
+	for _, l := range []*Logger{a, b, c} {
+		l.Info.Println("hello")
+		if err := l.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertLogFiles(t, firstDir, "2024-03-09.info.log")
+	assertLogFiles(t, secondDir, "2025-07-01.info.log")
+	assertLogFiles(t, plainDir, time.Now().Format("2006-01-02")+".info.log")
+	if got := a.now(); !got.Equal(first.now()) {
+		t.Errorf("a 的时间为 %v，应当为 %v", got, first.now())
+	}
+	if got := b.now(); !got.Equal(second.now()) {
+		t.Errorf("b 的时间为 %v，应当为 %v", got, second.now())
+	}
+}
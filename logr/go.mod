@@ -0,0 +1,10 @@
+module github.com/nickham-su/go_logger/logr
+
+go 1.21
+
+require (
+	github.com/go-logr/logr v1.4.4
+	github.com/nickham-su/go_logger v0.0.0
+)
+
+replace github.com/nickham-su/go_logger => ../
@@ -0,0 +1,109 @@
+// Package logr 提供 github.com/go-logr/logr 的 LogSink 实现，使 controller-runtime 等使用 logr 的组件
+// 将日志写入 go_logger 的日志文件：
+//
+//	ctrl.SetLogger(gologr.New(logger.Default()))
+//
+// logr 的 V(0) 对应 Info 级别，V(1) 及以上对应 Debug 级别。本包是独立的 module，只有使用时才会引入 logr 的依赖。
+package logr
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nickham-su/go_logger"
+)
+
+// New 返回写入 l 的 logr.Logger
+func New(l *logger.Logger) logr.Logger {
+	return logr.New(NewLogSink(l))
+}
+
+// NewLogSink 返回写入 l 的 logr.LogSink
+func NewLogSink(l *logger.Logger) logr.LogSink {
+	return &sink{handler: l.SlogHandler()}
+}
+
+// sink 将 logr 的调用转换为 slog.Record，交给本包的 slog.Handler 处理
+type sink struct {
+	handler slog.Handler
+	// name 是 WithName 累积的名称，以 / 分隔
+	name string
+	// depth 是 logr 与调用方之间额外的栈帧数
+	depth int
+}
+
+var (
+	_ logr.LogSink          = (*sink)(nil)
+	_ logr.CallDepthLogSink = (*sink)(nil)
+)
+
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.depth = info.CallDepth
+}
+
+// levelOf 将 logr 的 V 级别转换为 slog 的级别
+func levelOf(v int) slog.Level {
+	if v <= 0 {
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+func (s *sink) Enabled(v int) bool {
+	return s.handler.Enabled(context.Background(), levelOf(v))
+}
+
+func (s *sink) Info(v int, msg string, kv ...interface{}) {
+	s.log(levelOf(v), msg, kv)
+}
+
+func (s *sink) Error(err error, msg string, kv ...interface{}) {
+	s.log(slog.LevelError, msg, append(kv, "error", err))
+}
+
+// log 构造 slog.Record 并写入，调用方的位置按 Info 或 Error 被 logr.Logger 调用计算
+func (s *sink) log(level slog.Level, msg string, kv []interface{}) {
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3+s.depth, pcs[:])
+	r := slog.NewRecord(time.Time{}, level, msg, pcs[0])
+	if s.name != "" {
+		r.AddAttrs(slog.String("logger", s.name))
+	}
+	r.Add(kv...)
+	_ = s.handler.Handle(ctx, r)
+}
+
+func (s *sink) WithValues(kv ...interface{}) logr.LogSink {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(kv...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	c := *s
+	c.handler = s.handler.WithAttrs(attrs)
+	return &c
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name != "" {
+		c.name += "/"
+	}
+	c.name += name
+	return &c
+}
+
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	c := *s
+	c.depth += depth
+	return &c
+}
@@ -0,0 +1,135 @@
+// Package logtest 提供在测试中捕获和断言日志的工具
+package logtest
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/nickham-su/go_logger"
+	"github.com/nickham-su/go_logger/internal/testhook"
+)
+
+// Entry 是捕获到的一条日志
+type Entry struct {
+	Level   logger.Level
+	Message string
+	Fields  map[string]interface{}
+	Err     error
+}
+
+// Capture 记录一个日志实例输出的所有日志
+type Capture struct {
+	// Logger 是不写入日志文件、输出所有级别的实例，用于替换被测代码中的日志实例
+	Logger *logger.Logger
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewCapture 返回一个新的 Capture，使用完后应调用 Close
+func NewCapture() *Capture {
+	c := &Capture{Logger: logger.New()}
+	c.Logger.DisableFileOutput()
+	c.Logger.SetLevel(logger.LevelTrace)
+	c.Logger.AddHook(logger.HookFunc(c.record))
+	return c
+}
+
+func (c *Capture) record(e *logger.Entry) {
+	entry := Entry{Level: e.Level, Message: e.Message, Err: e.Err}
+	if len(e.Fields) > 0 {
+		entry.Fields = make(map[string]interface{}, len(e.Fields))
+		for _, f := range e.Fields {
+			entry.Fields[f.Key] = f.Value
+		}
+	}
+	c.mu.Lock()
+	c.entries = append(c.entries, entry)
+	c.mu.Unlock()
+}
+
+// Entries 返回目前捕获的所有日志
+func (c *Capture) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Entry(nil), c.entries...)
+}
+
+// Reset 清空捕获的日志
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// Close 关闭 Logger
+func (c *Capture) Close() error {
+	return c.Logger.Close()
+}
+
+// Find 返回级别为 level 且内容包含 substr 的日志
+func (c *Capture) Find(level logger.Level, substr string) []Entry {
+	var found []Entry
+	for _, e := range c.Entries() {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// AssertLogged 断言捕获过级别为 level 且内容包含 substr 的日志
+func (c *Capture) AssertLogged(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if len(c.Find(level, substr)) == 0 {
+		t.Errorf("没有级别为 %s 且包含 %q 的日志，捕获的日志：\n%s", level, substr, c.dump())
+	}
+}
+
+// AssertNotLogged 断言没有捕获过级别为 level 且内容包含 substr 的日志
+func (c *Capture) AssertNotLogged(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if found := c.Find(level, substr); len(found) > 0 {
+		t.Errorf("不应有级别为 %s 且包含 %q 的日志，但捕获到 %d 条", level, substr, len(found))
+	}
+}
+
+// AssertField 断言捕获过级别为 level、内容包含 substr 且字段 key 等于 value 的日志
+func (c *Capture) AssertField(t testing.TB, level logger.Level, substr, key string, value interface{}) {
+	t.Helper()
+	for _, e := range c.Find(level, substr) {
+		if v, ok := e.Fields[key]; ok && reflect.DeepEqual(v, value) {
+			return
+		}
+	}
+	t.Errorf("没有级别为 %s、包含 %q 且 %s=%v 的日志，捕获的日志：\n%s", level, substr, key, value, c.dump())
+}
+
+// dump 将捕获的日志格式化为多行文本，用于断言失败时的提示
+func (c *Capture) dump() string {
+	var b strings.Builder
+	for _, e := range c.Entries() {
+		b.WriteString("  ")
+		b.WriteString(e.Level.String())
+		b.WriteByte(' ')
+		b.WriteString(e.Message)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ResetDefault 关闭 logger 包的默认实例并以默认配置和 LOGGER_ 环境变量重新创建，同时恢复 Enable 和 SetClockForTesting 的修改，
+// 测试结束后再重置一次，用于测试之间隔离默认实例的状态，
+// 重置时替换 logger.Info 等包级别变量，不能与其他 goroutine 的日志输出并发调用，也不能用于调用了 t.Parallel 的测试
+func ResetDefault(t testing.TB) {
+	t.Helper()
+	reset := func() {
+		if err := testhook.ResetDefault(); err != nil {
+			t.Errorf("关闭默认实例失败：%v", err)
+		}
+	}
+	reset()
+	t.Cleanup(reset)
+}
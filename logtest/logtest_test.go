@@ -0,0 +1,32 @@
+package logtest
+
+import (
+	"testing"
+
+	logger "github.com/nickham-su/go_logger"
+)
+
+func TestResetDefault(t *testing.T) {
+	old := logger.Default()
+	logger.SetDir(t.TempDir())
+	logger.SetLevel(logger.LevelError)
+	logger.Disable()
+	ResetDefault(t)
+	if logger.Default() == old {
+		t.Fatal("默认实例应当被重新创建")
+	}
+	if logger.Info != logger.Default().Info || logger.Error != logger.Default().Error {
+		t.Error("包级别变量应当指向新的默认实例")
+	}
+	if got := logger.GetLevel(); got == logger.LevelError {
+		t.Errorf("最低级别应当恢复为默认值，得到 %s", got)
+	}
+	c := NewCapture()
+	defer c.Close()
+	logger.Default().AddHook(logger.HookFunc(func(e *logger.Entry) {
+		c.Logger.Info.Println(e.Message)
+	}))
+	logger.Default().DisableFileOutput()
+	logger.Info.Println("enabled again")
+	c.AssertLogged(t, logger.LevelInfo, "enabled again")
+}
@@ -0,0 +1,204 @@
+// Package loki 将 go_logger 的日志批量推送到 Grafana Loki 的 HTTP API。
+//
+//	c := loki.NewClient("http://loki:3100", loki.WithApp("order"))
+//	c.Attach(logger.Default())
+//	defer c.Close()
+package loki
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+	"github.com/nickham-su/go_logger/internal/batcher"
+)
+
+// pushPath 是 Loki 接收日志的接口路径
+const pushPath = "/loki/api/v1/push"
+
+// Option 用于 NewClient 的配置项
+type Option func(*Client)
+
+// WithApp 设置 app 标签
+func WithApp(app string) Option {
+	return func(c *Client) {
+		c.labels["app"] = app
+	}
+}
+
+// WithLabels 添加固定的标签，会覆盖同名的 host、app 标签
+func WithLabels(labels map[string]string) Option {
+	return func(c *Client) {
+		for k, v := range labels {
+			c.labels[k] = v
+		}
+	}
+}
+
+// WithBatchSize 设置每次推送的最大日志条数，默认为 100
+func WithBatchSize(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithBatchTimeout 设置未凑满一批时的最长等待时间，默认为 1 秒
+func WithBatchTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.batchTimeout = d
+		}
+	}
+}
+
+// WithHTTPClient 设置发送请求使用的 http.Client，默认为超时 10 秒的 http.Client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithErrorHandler 设置推送失败时的回调，默认忽略错误
+func WithErrorHandler(h func(err error)) Option {
+	return func(c *Client) {
+		c.onError = h
+	}
+}
+
+// Client 收集日志并按批推送到 Loki，每个级别是一个带有 level 标签的 stream，
+// 所有推送都由同一个后台 goroutine 按顺序完成，写入日志的调用方不会等待 Loki，
+// 网络错误、5xx 和 429 响应时这一批保留在队列开头，在下一个 batchTimeout 重试，
+// 其他 4xx 响应表示请求本身无法被接受（例如日志过旧），这一批被丢弃，等待推送的日志超过 10000 条时丢弃新的日志
+type Client struct {
+	url          string
+	labels       map[string]string
+	batchSize    int
+	batchTimeout time.Duration
+	http         *http.Client
+	onError      func(err error)
+	batch        *batcher.Batcher[entry]
+}
+
+// entry 是等待推送的一行日志
+type entry struct {
+	level string
+	time  time.Time
+	line  string
+}
+
+// NewClient 创建推送到 baseURL（例如 http://loki:3100）的 Client，默认带有 host 标签
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		url:          strings.TrimRight(baseURL, "/") + pushPath,
+		labels:       make(map[string]string),
+		batchSize:    100,
+		batchTimeout: time.Second,
+		http:         &http.Client{Timeout: 10 * time.Second},
+	}
+	if host, err := os.Hostname(); err == nil {
+		c.labels["host"] = host
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.batch = batcher.New(c.batchSize, c.batchTimeout, c.post, c.onError)
+	return c
+}
+
+// Attach 将 Client 添加到 l 的每个级别
+func (c *Client) Attach(l *logger.Logger) {
+	for _, level := range logger.Levels() {
+		l.AppendWriterFor(level, c.For(level))
+	}
+}
+
+// For 返回写入 level 级别日志的 io.Writer，用于 Logger.AppendWriterFor
+func (c *Client) For(level logger.Level) io.Writer {
+	return &levelWriter{c: c, level: strings.ToLower(level.String())}
+}
+
+// Flush 立即推送已收集的日志并等待推送完成
+func (c *Client) Flush() error {
+	return c.batch.Flush()
+}
+
+// Close 停止后台推送并推送剩余的日志
+func (c *Client) Close() error {
+	return c.batch.Close()
+}
+
+// pushRequest 是 Loki push 接口的请求体
+type pushRequest struct {
+	Streams []stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// post 推送一批日志，编码失败和除 429 以外的 4xx 响应重试也不会成功，标记为 batcher.Permanent
+func (c *Client) post(batch []entry) error {
+	// index 记录每个级别的 stream 在 req.Streams 中的位置
+	index := make(map[string]int)
+	var req pushRequest
+	for _, e := range batch {
+		i, ok := index[e.level]
+		if !ok {
+			labels := make(map[string]string, len(c.labels)+1)
+			for k, v := range c.labels {
+				labels[k] = v
+			}
+			labels["level"] = e.level
+			i = len(req.Streams)
+			index[e.level] = i
+			req.Streams = append(req.Streams, stream{Stream: labels})
+		}
+		req.Streams[i].Values = append(req.Streams[i].Values, [2]string{strconv.FormatInt(e.time.UnixNano(), 10), e.line})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return batcher.Permanent(err)
+	}
+	resp, err := c.http.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("推送日志到 Loki 失败：%s %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			return batcher.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// levelWriter 将写入的每行日志记录为 level 级别的 entry
+type levelWriter struct {
+	c     *Client
+	level string
+}
+
+func (lw *levelWriter) Write(p []byte) (int, error) {
+	lw.c.batch.Add(entry{level: lw.level, time: time.Now(), line: string(bytes.TrimSuffix(p, []byte("\n")))})
+	return len(p), nil
+}
+
+// Flush 使 Logger.Sync 能够立即推送已收集的日志
+func (lw *levelWriter) Flush() error {
+	return lw.c.Flush()
+}
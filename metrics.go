@@ -0,0 +1,50 @@
+package logger
+
+import "sync/atomic"
+
+// Metrics 是日志实例自身的统计数据
+type Metrics struct {
+	// Lines 是各级别成功写入的日志条数
+	Lines map[Level]uint64
+	// BytesWritten 是成功写入的字节数
+	BytesWritten uint64
+	// Dropped 是异步模式或网络输出因队列已满而丢弃的日志条数
+	Dropped uint64
+	// WriteErrors 是写入文件或 writer 失败的次数
+	WriteErrors uint64
+}
+
+// metrics 是 Metrics 的内部计数器，各级别的日志条数记录在对应的 logger 中
+type metrics struct {
+	bytesWritten atomic.Uint64
+	dropped      atomic.Uint64
+	writeErrors  atomic.Uint64
+}
+
+// GetMetrics 返回默认实例的统计数据
+func GetMetrics() Metrics {
+	return std.Metrics()
+}
+
+// Metrics 返回该实例当前的统计数据
+func (l *Logger) Metrics() Metrics {
+	m := Metrics{
+		Lines:        make(map[Level]uint64),
+		BytesWritten: l.metrics.bytesWritten.Load(),
+		Dropped:      l.metrics.dropped.Load(),
+		WriteErrors:  l.metrics.writeErrors.Load(),
+	}
+	for _, lv := range l.levels() {
+		m.Lines[lv.level] = lv.lines.Load()
+	}
+	return m
+}
+
+// recordWrite 记录一次写入 count 条日志的结果，失败的次数已由 multiWriter 记录
+func (m *metrics) recordWrite(lines *atomic.Uint64, count, n int, err error) {
+	if err != nil {
+		return
+	}
+	lines.Add(uint64(count))
+	m.bytesWritten.Add(uint64(n))
+}
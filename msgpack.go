@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// encodeMsgpack 将 e 编码为一个 msgpack map，ts 使用 msgpack 的时间戳扩展类型，其余键与 JSON 格式相同
+func encodeMsgpack(b *bytes.Buffer, e *Entry, opts encodeOptions) {
+	n := 3 + len(opts.identity) + len(e.Fields)
+	var cause string
+	if e.Module != "" {
+		n++
+	}
+	if e.Caller != "" {
+		n++
+	}
+	if e.Err != nil {
+		n++
+		if causes := errorCauses(e.Err); len(causes) > 0 {
+			cause = causes[len(causes)-1].Error()
+			n++
+		}
+	}
+	if e.Stack != "" {
+		n++
+	}
+	writeMsgpackMapHeader(b, n)
+	writeMsgpackString(b, "ts")
+	writeMsgpackTime(b, e.Time)
+	writeMsgpackString(b, "level")
+	writeMsgpackString(b, e.Level.lowerString())
+	if e.Module != "" {
+		writeMsgpackString(b, "module")
+		writeMsgpackString(b, e.Module)
+	}
+	writeMsgpackString(b, "msg")
+	writeMsgpackString(b, e.Message)
+	if e.Caller != "" {
+		writeMsgpackString(b, "caller")
+		writeMsgpackString(b, e.Caller)
+	}
+	for _, fields := range [][]Field{opts.identity, e.Fields} {
+		for _, f := range fields {
+			writeMsgpackString(b, f.Key)
+			writeMsgpackValue(b, fieldValue(f.Value))
+		}
+	}
+	if e.Err != nil {
+		writeMsgpackString(b, "error")
+		writeMsgpackString(b, e.Err.Error())
+		if cause != "" {
+			writeMsgpackString(b, "cause")
+			writeMsgpackString(b, cause)
+		}
+	}
+	if e.Stack != "" {
+		writeMsgpackString(b, "stack")
+		writeMsgpackString(b, e.Stack)
+	}
+}
+
+// writeMsgpackValue 写入常见类型的值，其他类型先编码为 JSON 再转换，无法编码的值写为 fmt.Sprint 的结果
+func writeMsgpackValue(b *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if val {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case string:
+		writeMsgpackString(b, val)
+	case []byte:
+		writeMsgpackBinary(b, val)
+	case int:
+		writeMsgpackInt(b, int64(val))
+	case int8:
+		writeMsgpackInt(b, int64(val))
+	case int16:
+		writeMsgpackInt(b, int64(val))
+	case int32:
+		writeMsgpackInt(b, int64(val))
+	case int64:
+		writeMsgpackInt(b, val)
+	case uint:
+		writeMsgpackUint(b, uint64(val))
+	case uint8:
+		writeMsgpackUint(b, uint64(val))
+	case uint16:
+		writeMsgpackUint(b, uint64(val))
+	case uint32:
+		writeMsgpackUint(b, uint64(val))
+	case uint64:
+		writeMsgpackUint(b, val)
+	case float32:
+		b.WriteByte(0xca)
+		_ = binary.Write(b, binary.BigEndian, math.Float32bits(val))
+	case float64:
+		b.WriteByte(0xcb)
+		_ = binary.Write(b, binary.BigEndian, math.Float64bits(val))
+	case time.Time:
+		writeMsgpackTime(b, val)
+	case time.Duration:
+		writeMsgpackString(b, val.String())
+	case map[string]interface{}:
+		writeMsgpackMapHeader(b, len(val))
+		for k, item := range val {
+			writeMsgpackString(b, k)
+			writeMsgpackValue(b, item)
+		}
+	case []interface{}:
+		writeMsgpackArrayHeader(b, len(val))
+		for _, item := range val {
+			writeMsgpackValue(b, item)
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			writeMsgpackArrayHeader(b, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				writeMsgpackValue(b, fieldValue(rv.Index(i).Interface()))
+			}
+			return
+		}
+		data, err := json.Marshal(v)
+		var decoded interface{}
+		if err == nil {
+			err = json.Unmarshal(data, &decoded)
+		}
+		if err != nil {
+			writeMsgpackString(b, fmt.Sprint(v))
+			return
+		}
+		writeMsgpackValue(b, decoded)
+	}
+}
+
+func writeMsgpackString(b *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		b.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		b.WriteByte(0xd9)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xda)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(0xdb)
+		_ = binary.Write(b, binary.BigEndian, uint32(n))
+	}
+	b.WriteString(s)
+}
+
+func writeMsgpackBinary(b *bytes.Buffer, p []byte) {
+	n := len(p)
+	switch {
+	case n <= math.MaxUint8:
+		b.WriteByte(0xc4)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xc5)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(0xc6)
+		_ = binary.Write(b, binary.BigEndian, uint32(n))
+	}
+	b.Write(p)
+}
+
+func writeMsgpackInt(b *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0:
+		writeMsgpackUint(b, uint64(v))
+	case v >= -32:
+		b.WriteByte(byte(v))
+	case v >= math.MinInt8:
+		b.WriteByte(0xd0)
+		b.WriteByte(byte(v))
+	case v >= math.MinInt16:
+		b.WriteByte(0xd1)
+		_ = binary.Write(b, binary.BigEndian, int16(v))
+	case v >= math.MinInt32:
+		b.WriteByte(0xd2)
+		_ = binary.Write(b, binary.BigEndian, int32(v))
+	default:
+		b.WriteByte(0xd3)
+		_ = binary.Write(b, binary.BigEndian, v)
+	}
+}
+
+func writeMsgpackUint(b *bytes.Buffer, v uint64) {
+	switch {
+	case v < 128:
+		b.WriteByte(byte(v))
+	case v <= math.MaxUint8:
+		b.WriteByte(0xcc)
+		b.WriteByte(byte(v))
+	case v <= math.MaxUint16:
+		b.WriteByte(0xcd)
+		_ = binary.Write(b, binary.BigEndian, uint16(v))
+	case v <= math.MaxUint32:
+		b.WriteByte(0xce)
+		_ = binary.Write(b, binary.BigEndian, uint32(v))
+	default:
+		b.WriteByte(0xcf)
+		_ = binary.Write(b, binary.BigEndian, v)
+	}
+}
+
+func writeMsgpackMapHeader(b *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		b.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xde)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(0xdf)
+		_ = binary.Write(b, binary.BigEndian, uint32(n))
+	}
+}
+
+func writeMsgpackArrayHeader(b *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		b.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xdc)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(0xdd)
+		_ = binary.Write(b, binary.BigEndian, uint32(n))
+	}
+}
+
+// writeMsgpackTime 以 timestamp 96 扩展类型（ext 8，类型 -1）写入 t
+func writeMsgpackTime(b *bytes.Buffer, t time.Time) {
+	b.WriteByte(0xc7)
+	b.WriteByte(12)
+	b.WriteByte(0xff)
+	_ = binary.Write(b, binary.BigEndian, uint32(t.Nanosecond()))
+	_ = binary.Write(b, binary.BigEndian, t.Unix())
+}
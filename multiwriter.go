@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrorHandler 在某个 writer 写入失败时被调用，w 为出错的 writer，HandleSignal 重新打开文件失败时为 nil
+type ErrorHandler func(w io.Writer, err error)
+
+// SetErrorHandler 设置默认实例的写入错误回调
+func SetErrorHandler(h ErrorHandler) {
+	std.SetErrorHandler(h)
+}
+
+// SetErrorHandler 设置写入错误回调，某个 writer 出错时会跳过它继续写入其余的 writer，h 为 nil 时忽略错误
+func (l *Logger) SetErrorHandler(h ErrorHandler) {
+	if h == nil {
+		l.errorHandler.Store(nil)
+		return
+	}
+	l.errorHandler.Store(&h)
+}
+
+// multiWriter 依次写入所有 writer，与 io.MultiWriter 不同，某个 writer 出错不会中断后续的写入
+type multiWriter struct {
+	owner   *Logger
+	writers []io.Writer
+}
+
+func (l *Logger) newMultiWriter(writers []io.Writer) *multiWriter {
+	return &multiWriter{owner: l, writers: writers}
+}
+
+// Write 总是写入所有 writer，只要有一个 writer 写入成功就不返回错误
+func (m *multiWriter) Write(p []byte) (int, error) {
+	return m.writeBatch(p, nil)
+}
+
+// writeBatch 与 Write 相同，ends 不为 nil 时 p 由多条日志组成，ends 为每条日志在 p 中的结束位置，
+// 日志文件和 *os.File 一次写入整批，其他 writer 可能按条处理（例如每次写入发送一条消息），仍逐条写入
+func (m *multiWriter) writeBatch(p []byte, ends []int) (int, error) {
+	var firstErr error
+	ok := len(m.writers) == 0
+	for _, w := range m.writers {
+		n, err := writeEntries(w, p, ends)
+		if err == nil && n != len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			if lw, ok := w.(*lockedWriter); ok {
+				w = lw.w
+			}
+			m.owner.reportError(w, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ok = true
+	}
+	if ok {
+		return len(p), nil
+	}
+	return 0, firstErr
+}
+
+// writeEntries 将 p 写入 w，ends 的含义与 writeBatch 相同，返回写入的总字节数
+func writeEntries(w io.Writer, p []byte, ends []int) (int, error) {
+	switch w.(type) {
+	case *fileSink, *os.File:
+		return w.Write(p)
+	}
+	if ends == nil {
+		return w.Write(p)
+	}
+	total, start := 0, 0
+	for _, end := range ends {
+		n, err := w.Write(p[start:end])
+		total += n
+		if err != nil {
+			return total, err
+		}
+		start = end
+	}
+	return total, nil
+}
+
+// reportError 记录写入失败的次数，输出到 SetInternalLogger 设置的 writer 并调用错误回调
+func (l *Logger) reportError(w io.Writer, err error) {
+	l.metrics.writeErrors.Add(1)
+	if w == nil {
+		l.internalf("%v", err)
+	} else {
+		l.internalf("写入%s失败：%v", writerName(w), err)
+	}
+	if h := l.errorHandler.Load(); h != nil {
+		(*h)(w, err)
+	}
+}
+
+// concurrentWriter 由本包内可以被并发写入的 writer 实现，添加时不需要加锁
+type concurrentWriter interface {
+	concurrentSafe()
+}
+
+// lockWriters 为不能确定并发安全的 writer 加锁，同一个 writer 可能同时被多个级别的日志写入
+func lockWriters(writers []io.Writer) []io.Writer {
+	locked := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		switch w.(type) {
+		case *os.File, concurrentWriter, *lockedWriter:
+			locked[i] = w
+		default:
+			locked[i] = &lockedWriter{w: w}
+		}
+	}
+	return locked
+}
+
+// lockedWriter 串行化对一个 writer 的写入
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// Flush 使 Sync 能够刷新被包装的 writer，被包装的 writer 只有 Sync 方法时调用 Sync
+func (w *lockedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch f := w.w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Sync() error }:
+		return f.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package logger
+
+// Named 返回默认实例中模块 name 的一组日志记录器
+func Named(name string) *contextLogger {
+	return std.Named(name)
+}
+
+// Named 返回模块 name 的一组日志记录器，日志内容前会加上 [name]，JSON 格式下输出为 module 字段，
+// 可以通过 SetModuleLevel 单独设置该模块的最低级别
+func (l *Logger) Named(name string) *contextLogger {
+	named := func(lv *logger, expandErr bool) *fieldLogger {
+		return &fieldLogger{logger: lv, expandErr: expandErr, module: name}
+	}
+	return &contextLogger{
+		Trace:   named(l.Trace, false),
+		Debug:   named(l.Debug, false),
+		Info:    named(l.Info, false),
+		Warning: named(l.Warning, false),
+		Error:   named(&l.Error.logger, true),
+	}
+}
+
+// SetModuleLevel 设置默认实例中模块 name 的最低级别
+func SetModuleLevel(name string, level Level) {
+	std.SetModuleLevel(name, level)
+}
+
+// SetModuleLevel 设置模块 name 的最低级别，优先于 SetLevel 设置的级别，
+// 例如只对 db 模块输出 DEBUG 日志：SetModuleLevel("db", LevelDebug)
+func (l *Logger) SetModuleLevel(name string, level Level) {
+	l.updateModuleLevels(func(m map[string]Level) {
+		m[name] = level
+	})
+}
+
+// ResetModuleLevel 取消默认实例中模块 name 的级别设置
+func ResetModuleLevel(name string) {
+	std.ResetModuleLevel(name)
+}
+
+// ResetModuleLevel 取消模块 name 的级别设置，之后使用 SetLevel 设置的级别
+func (l *Logger) ResetModuleLevel(name string) {
+	l.updateModuleLevels(func(m map[string]Level) {
+		delete(m, name)
+	})
+}
+
+// updateModuleLevels 复制当前的模块级别修改后整体替换，读取时无需加锁
+func (l *Logger) updateModuleLevels(update func(m map[string]Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := make(map[string]Level)
+	if old := l.moduleLevels.Load(); old != nil {
+		for k, v := range *old {
+			m[k] = v
+		}
+	}
+	update(m)
+	l.moduleLevels.Store(&m)
+}
+
+// moduleLevel 返回模块 module 的最低级别，未设置时 ok 为 false
+func (l *Logger) moduleLevel(module string) (level Level, ok bool) {
+	if module == "" {
+		return 0, false
+	}
+	m := l.moduleLevels.Load()
+	if m == nil {
+		return 0, false
+	}
+	level, ok = (*m)[module]
+	return level, ok
+}
@@ -0,0 +1,107 @@
+package logger
+
+// rotateEvent 是一次日志文件的切换，compressed 为 true 时表示 oldPath 被压缩为 newPath
+type rotateEvent struct {
+	oldPath, newPath string
+	compressed       bool
+}
+
+// OnRotate 为默认实例添加切换日志文件时的回调
+func OnRotate(f func(oldPath, newPath string)) {
+	std.OnRotate(f)
+}
+
+// OnRotate 添加切换日志文件时的回调，按天轮转或按大小切分后在后台 goroutine 中按发生的顺序调用，
+// oldPath 是已写完的文件，newPath 是之后写入的文件，可以在回调中上传 oldPath 或通知日志采集程序，
+// 修改目录或文件名格式等导致的切换也会触发回调，多个回调按添加的顺序调用
+func (l *Logger) OnRotate(f func(oldPath, newPath string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var callbacks []func(oldPath, newPath string)
+	if old := l.rotateCallbacks.Load(); old != nil {
+		callbacks = append(callbacks, *old...)
+	}
+	callbacks = append(callbacks, f)
+	l.rotateCallbacks.Store(&callbacks)
+}
+
+// OnCompress 为默认实例添加压缩历史日志文件后的回调
+func OnCompress(f func(path, gzPath string)) {
+	std.OnCompress(f)
+}
+
+// OnCompress 添加压缩历史日志文件后的回调，path 是已被删除的原文件，gzPath 是压缩后的文件，
+// 与 OnRotate 的回调在同一个后台 goroutine 中按发生的顺序调用
+func (l *Logger) OnCompress(f func(path, gzPath string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var callbacks []func(path, gzPath string)
+	if old := l.compressCallbacks.Load(); old != nil {
+		callbacks = append(callbacks, *old...)
+	}
+	callbacks = append(callbacks, f)
+	l.compressCallbacks.Store(&callbacks)
+}
+
+// CompressEnabled 返回是否会在切换后压缩历史日志文件，开启 SetStreamCompression 时写完的文件已经是压缩文件，返回 false
+func (l *Logger) CompressEnabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.compress && !l.streamCompress.Load()
+}
+
+// notifyRotate 记录一次切换，由后台 goroutine 调用回调，避免阻塞写入
+func (l *Logger) notifyRotate(oldPath, newPath string) {
+	if l.rotateCallbacks.Load() == nil && !l.checksums.Load() || oldPath == newPath {
+		return
+	}
+	l.addRotateEvent(rotateEvent{oldPath: oldPath, newPath: newPath})
+}
+
+// notifyCompress 记录一次压缩，由后台 goroutine 调用回调
+func (l *Logger) notifyCompress(path, gzPath string) {
+	if l.compressCallbacks.Load() == nil && !l.checksums.Load() {
+		return
+	}
+	l.addRotateEvent(rotateEvent{oldPath: path, newPath: gzPath, compressed: true})
+}
+
+func (l *Logger) addRotateEvent(event rotateEvent) {
+	l.rotateEventsMu.Lock()
+	defer l.rotateEventsMu.Unlock()
+	l.rotateEvents = append(l.rotateEvents, event)
+	if len(l.rotateEvents) == 1 {
+		l.goBackground(l.dispatchRotateEvents)
+	}
+}
+
+// dispatchRotateEvents 依次处理队列中的切换，直到队列为空
+func (l *Logger) dispatchRotateEvents() {
+	for {
+		l.rotateEventsMu.Lock()
+		if len(l.rotateEvents) == 0 {
+			l.rotateEventsMu.Unlock()
+			return
+		}
+		event := l.rotateEvents[0]
+		l.rotateEventsMu.Unlock()
+		if event.compressed {
+			l.recordChecksum(event.newPath, event.oldPath)
+			if callbacks := l.compressCallbacks.Load(); callbacks != nil {
+				for _, f := range *callbacks {
+					f(event.oldPath, event.newPath)
+				}
+			}
+		} else {
+			l.recordChecksum(event.oldPath, "")
+			if callbacks := l.rotateCallbacks.Load(); callbacks != nil {
+				for _, f := range *callbacks {
+					f(event.oldPath, event.newPath)
+				}
+			}
+		}
+		l.rotateEventsMu.Lock()
+		l.rotateEvents = l.rotateEvents[1:]
+		l.rotateEventsMu.Unlock()
+	}
+}
@@ -0,0 +1,106 @@
+// Package otel 将 go_logger 的日志通过 OpenTelemetry Logs API 发出，由 SDK 配置的 exporter（例如 OTLP）导出。
+//
+// 使用 WithContext 传入的 context 中的 span 会由 SDK 记录为日志的 trace ID 和 span ID：
+//
+//	otel.Attach(logger.Default(), provider)
+//	logger.WithContext(ctx).Info.Println("order created")
+//
+// 本包是独立的 module，只有使用时才会引入 OpenTelemetry 的依赖。
+package otel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// instrumentationName 是传给 LoggerProvider 的 instrumentation scope 名称
+const instrumentationName = "github.com/nickham-su/go_logger"
+
+// Attach 为 l 添加将日志发送到 provider 的 Hook，provider 为 nil 时使用全局的 LoggerProvider
+func Attach(l *logger.Logger, provider log.LoggerProvider) {
+	l.AddHook(NewHook(provider))
+}
+
+// NewHook 返回将日志发送到 provider 的 Hook，provider 为 nil 时使用全局的 LoggerProvider
+func NewHook(provider log.LoggerProvider) logger.Hook {
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+	return &hook{logger: provider.Logger(instrumentationName)}
+}
+
+type hook struct {
+	logger log.Logger
+}
+
+func (h *hook) Fire(e *logger.Entry) {
+	severity := severityOf(e.Level)
+	var r log.Record
+	r.SetTimestamp(e.Time)
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(severity)
+	r.SetSeverityText(e.Level.String())
+	r.SetBody(attribute.StringValue(e.Message))
+	r.SetErr(e.Err)
+	attrs := make([]attribute.KeyValue, 0, len(e.Fields)+1)
+	for _, f := range e.Fields {
+		attrs = append(attrs, attribute.KeyValue{Key: attribute.Key(f.Key), Value: value(f.Value)})
+	}
+	if e.Caller != "" {
+		attrs = append(attrs, attribute.String("caller", e.Caller))
+	}
+	r.AddAttributes(attrs...)
+	h.logger.Emit(e.Context, r)
+}
+
+// severityOf 将日志级别转换为 OpenTelemetry 的严重级别
+func severityOf(level logger.Level) log.Severity {
+	switch level {
+	case logger.LevelDebug:
+		return log.SeverityDebug
+	case logger.LevelInfo:
+		return log.SeverityInfo
+	case logger.LevelWarning:
+		return log.SeverityWarn
+	default:
+		return log.SeverityError
+	}
+}
+
+// value 将字段的值转换为 attribute.Value，无法直接表示的类型使用 fmt.Sprint
+func value(v interface{}) attribute.Value {
+	switch val := v.(type) {
+	case nil:
+		return attribute.Value{}
+	case string:
+		return attribute.StringValue(val)
+	case bool:
+		return attribute.BoolValue(val)
+	case int:
+		return attribute.IntValue(val)
+	case int32:
+		return attribute.Int64Value(int64(val))
+	case int64:
+		return attribute.Int64Value(val)
+	case uint32:
+		return attribute.Int64Value(int64(val))
+	case float32:
+		return attribute.Float64Value(float64(val))
+	case float64:
+		return attribute.Float64Value(val)
+	case []byte:
+		return attribute.ByteSliceValue(val)
+	case time.Duration:
+		return attribute.StringValue(val.String())
+	case error:
+		return attribute.StringValue(val.Error())
+	case fmt.Stringer:
+		return attribute.StringValue(val.String())
+	}
+	return attribute.StringValue(fmt.Sprint(v))
+}
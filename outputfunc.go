@@ -0,0 +1,27 @@
+package logger
+
+// SetOutputFunc 为默认实例设置接收每条日志的函数
+func SetOutputFunc(f func(e Entry)) {
+	std.SetOutputFunc(f)
+}
+
+// SetOutputFunc 设置接收每条日志的函数，f 在 Hook 之后、编码之前被同步调用，可以直接读取级别、时间、
+// 内容、字段和调用方等信息，不影响写入文件和 writer，f 为 nil 时取消
+func (l *Logger) SetOutputFunc(f func(e Entry)) {
+	if f == nil {
+		l.outputFunc.Store(nil)
+		return
+	}
+	l.outputFunc.Store(&f)
+}
+
+// callOutputFunc 将 e 的副本传给 SetOutputFunc 设置的函数
+func (l *Logger) callOutputFunc(e *Entry) {
+	f := l.outputFunc.Load()
+	if f == nil {
+		return
+	}
+	c := *e
+	c.Fields = append([]Field(nil), e.Fields...)
+	(*f)(c)
+}
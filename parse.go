@@ -0,0 +1,348 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatAuto 表示按每一行的开头判断输出格式，只用于解析
+const formatAuto Format = -1
+
+// maxParseLine 是解析时一行日志的最大字节数
+const maxParseLine = 64 << 20
+
+// ErrEncrypted 是解析加密的日志文件而没有通过 WithParseKey 指定密钥时返回的错误
+var ErrEncrypted = errors.New("日志文件已加密，需要使用 WithParseKey 指定密钥")
+
+// ParseOption 用于 ParseFile、NewParser 的配置项
+type ParseOption func(*Parser)
+
+// WithParseFormat 指定日志的格式，默认按每一行的开头自动判断文本、JSON 或 logfmt 格式
+func WithParseFormat(f Format) ParseOption {
+	return func(p *Parser) {
+		p.p.format = f
+	}
+}
+
+// WithParseTimeFormat 指定写入时 SetTimeFormat 设置的时间格式，默认为各格式默认的时间格式
+func WithParseTimeFormat(layout string) ParseOption {
+	return func(p *Parser) {
+		p.p.timeFormat = layout
+	}
+}
+
+// WithParseLocation 指定不含时区的时间戳所在的时区，默认为 time.Local
+func WithParseLocation(loc *time.Location) ParseOption {
+	return func(p *Parser) {
+		if loc != nil {
+			p.p.loc = loc
+		}
+	}
+}
+
+// WithParseKey 指定 SetEncryptionKey 设置的密钥，用于解析加密的日志文件
+func WithParseKey(key []byte) ParseOption {
+	return func(p *Parser) {
+		p.key = key
+	}
+}
+
+// Parser 逐条读取本包写入的日志文件
+type Parser struct {
+	p       entryParser
+	key     []byte
+	scanner *bufio.Scanner
+	// pending 是已读取、但可能还有后续调用栈行的文本日志
+	pending    Entry
+	hasPending bool
+}
+
+// NewParser 返回从 r 中读取日志的 Parser，r 可以是 gzip 压缩后的文件
+func NewParser(r io.Reader, opts ...ParseOption) (*Parser, error) {
+	p := &Parser{p: entryParser{format: formatAuto, loc: time.Local}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.key != nil {
+		var err error
+		if r, err = NewDecryptReader(r, p.key); err != nil {
+			return nil, err
+		}
+	} else {
+		br := bufio.NewReader(r)
+		if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+			gz, err := gzip.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			r = gz
+		} else {
+			r = br
+		}
+	}
+	p.scanner = bufio.NewScanner(r)
+	p.scanner.Buffer(nil, maxParseLine)
+	return p, nil
+}
+
+// Next 返回下一条日志，读完时返回 io.EOF，无法解析的行被忽略，文本格式中跨行的调用栈记录在 Stack 中，
+// 文本格式中的字段和调用方保留在 Message 中
+func (p *Parser) Next() (Entry, error) {
+	for p.scanner.Scan() {
+		line := p.scanner.Text()
+		if strings.HasPrefix(line, string(recordMagic)) {
+			return Entry{}, ErrEncrypted
+		}
+		e, ok := p.p.parseLine(line)
+		if !ok {
+			if p.hasPending && line != "" && p.p.continues(line) {
+				p.pending.appendStack(line)
+			}
+			continue
+		}
+		if p.hasPending {
+			prev := p.pending
+			p.pending = e
+			return prev, nil
+		}
+		p.pending, p.hasPending = e, true
+	}
+	if err := p.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	if p.hasPending {
+		p.hasPending = false
+		return p.pending, nil
+	}
+	return Entry{}, io.EOF
+}
+
+// ParseFile 读取日志文件 path 中的所有日志，支持 RegisterCodec 注册的格式压缩和加密后的文件，用于将日志导入其他系统，
+// 大文件应当使用 OpenLogFile 和 NewParser 逐条读取
+func ParseFile(path string, opts ...ParseOption) ([]Entry, error) {
+	f, err := OpenLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := NewParser(f, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// entryParser 将编码后的一行日志解析为 Entry
+type entryParser struct {
+	// format 为 formatAuto 时按每一行的开头判断格式
+	format Format
+	// timeFormat 是时间戳的格式，为空时使用各格式默认的时间格式
+	timeFormat string
+	loc        *time.Location
+}
+
+// newEntryParser 返回按当前的输出格式解析日志的 entryParser
+func (l *Logger) newEntryParser() (*entryParser, error) {
+	opts := l.encodeOptions()
+	if opts.encoder != nil || opts.format == formatMsgpack {
+		return nil, errUnreadableFormat
+	}
+	return &entryParser{format: opts.format, timeFormat: opts.timeFormat, loc: l.now().Location()}, nil
+}
+
+// layout 返回格式 f 的时间戳格式
+func (p *entryParser) layout(f Format) string {
+	if p.timeFormat != "" {
+		return p.timeFormat
+	}
+	if f == FormatText {
+		return defaultTextTimeFormat
+	}
+	return defaultJSONTimeFormat
+}
+
+// parse 解析 data 中的每一行，无法解析的行被忽略，文本格式中无法解析的行作为上一条日志的调用栈
+func (p *entryParser) parse(data []byte) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		e, ok := p.parseLine(line)
+		if ok {
+			entries = append(entries, e)
+		} else if len(entries) > 0 && line != "" && p.continues(line) {
+			entries[len(entries)-1].appendStack(line)
+		}
+	}
+	return entries
+}
+
+// parseLine 解析一行日志，文本格式的调用栈等不是一条日志开头的行返回 false
+func (p *entryParser) parseLine(line string) (Entry, bool) {
+	switch p.lineFormat(line) {
+	case FormatJSON:
+		return p.parseJSON(line)
+	case FormatLogfmt:
+		return p.parseLogfmt(line)
+	default:
+		return p.parseText(line)
+	}
+}
+
+// lineFormat 返回 line 的格式
+func (p *entryParser) lineFormat(line string) Format {
+	if p.format != formatAuto {
+		return p.format
+	}
+	switch {
+	case strings.HasPrefix(line, "{"):
+		return FormatJSON
+	case strings.HasPrefix(line, "ts="):
+		return FormatLogfmt
+	}
+	return FormatText
+}
+
+// continues 判断无法解析的 line 是否为上一条文本日志的调用栈
+func (p *entryParser) continues(line string) bool {
+	return p.lineFormat(line) == FormatText
+}
+
+// appendStack 将一行调用栈追加到 e.Stack
+func (e *Entry) appendStack(line string) {
+	if e.Stack != "" {
+		e.Stack += "\n"
+	}
+	e.Stack += line
+}
+
+// parseText 解析 时间 级别 [模块] 内容 形式的文本日志
+func (p *entryParser) parseText(line string) (Entry, bool) {
+	layout := p.layout(FormatText)
+	end := -1
+	for i := strings.Count(layout, " "); i >= 0; i-- {
+		j := strings.IndexByte(line[end+1:], ' ')
+		if j < 0 {
+			return Entry{}, false
+		}
+		end += j + 1
+	}
+	t, err := time.ParseInLocation(layout, line[:end], p.loc)
+	if err != nil {
+		return Entry{}, false
+	}
+	rest := line[end+1:]
+	name, msg, _ := strings.Cut(rest, " ")
+	level, ok := lookupLevel(name)
+	if !ok {
+		return Entry{}, false
+	}
+	e := Entry{Time: t, Level: level}
+	if strings.HasPrefix(msg, "[") {
+		if module, after, ok := strings.Cut(msg[1:], "] "); ok {
+			e.Module, msg = module, after
+		}
+	}
+	if i := strings.LastIndex(msg, " caller="); i >= 0 && !strings.Contains(msg[i+len(" caller="):], " ") {
+		e.Caller = msg[i+len(" caller="):]
+		msg = msg[:i]
+	}
+	e.Message = msg
+	return e, true
+}
+
+// parseJSON 解析一行 JSON 格式的日志，字段保持原来的顺序
+func (p *entryParser) parseJSON(line string) (Entry, bool) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Entry{}, false
+	}
+	var e Entry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Entry{}, false
+		}
+		key, _ := tok.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return Entry{}, false
+		}
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				v = i
+			} else if f, err := n.Float64(); err == nil {
+				v = f
+			}
+		}
+		p.set(&e, key, v, FormatJSON)
+	}
+	return e, !e.Time.IsZero()
+}
+
+// parseLogfmt 解析一行 logfmt 格式的日志
+func (p *entryParser) parseLogfmt(line string) (Entry, bool) {
+	var e Entry
+	for line != "" {
+		line = strings.TrimLeft(line, " ")
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return Entry{}, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else if i := strings.IndexByte(rest, ' '); i >= 0 {
+			value, rest = rest[:i], rest[i:]
+		} else {
+			value, rest = rest, ""
+		}
+		p.set(&e, key, value, FormatLogfmt)
+		line = rest
+	}
+	return e, !e.Time.IsZero()
+}
+
+// set 将结构化日志中的一个键值对设置到 e 中，不是内置字段的键值对追加到 Fields
+func (p *entryParser) set(e *Entry, key string, v interface{}, f Format) {
+	s, isString := v.(string)
+	switch {
+	case key == "ts" && isString:
+		e.Time, _ = time.ParseInLocation(p.layout(f), s, p.loc)
+	case key == "level" && isString:
+		e.Level, _ = ParseLevel(s)
+	case key == "module" && isString:
+		e.Module = s
+	case key == "msg" && isString:
+		e.Message = s
+	case key == "caller" && isString:
+		e.Caller = s
+	case key == "error" && isString:
+		e.Err = errors.New(s)
+	case key == "stack" && isString:
+		e.Stack = s
+	default:
+		e.Fields = append(e.Fields, Field{Key: key, Value: v})
+	}
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultFilePattern 是默认的日志文件名格式
+const defaultFilePattern = "{date}.{level}.log"
+
+// periodPattern 匹配文件名中的时间部分，例如 2006-01-02、2006-01-02_15、2006-01-02_1504
+const periodPattern = `\d{4}-\d{2}-\d{2}(?:_\d{2}(?:\d{2})?)?`
+
+// policyPeriodPattern 匹配设置了轮转策略时文件名中的时间部分，即 RotationPolicy.NextName 的返回值
+const policyPeriodPattern = `[\w-]+`
+
+var patternTokenRegexp = regexp.MustCompile(`\{(app|hostname|pid|date|level)\}`)
+
+// hostname 返回 {hostname} 使用的主机名，测试中可以替换
+var hostname = os.Hostname
+
+// SetFilePattern 设置默认实例的日志文件名格式
+func SetFilePattern(pattern string) {
+	std.SetFilePattern(pattern)
+}
+
+// SetFilePattern 设置日志文件名格式，支持 {app}、{hostname}、{pid}、{date}、{level}，
+// 例如 "{app}-{date}.{level}.log"，按大小切分时序号插入在扩展名之前，扩展名取最后一个占位符之后的部分，
+// 没有时序号追加在末尾，为空时恢复默认的 "{date}.{level}.log"
+func (l *Logger) SetFilePattern(pattern string) {
+	l.mu.Lock()
+	l.filePattern = pattern
+	l.mu.Unlock()
+	l.rotate()
+}
+
+// pattern 返回当前生效的文件名格式，合并输出时去掉 {level} 及其前面的分隔符，调用方需持有 l.mu
+func (l *Logger) pattern() string {
+	p := l.filePattern
+	if p == "" {
+		p = defaultFilePattern
+	}
+	if l.combined {
+		i := strings.Index(p, "{level}")
+		for i >= 0 {
+			start, end := i, i+len("{level}")
+			if start > 0 && strings.ContainsRune(".-_", rune(p[start-1])) {
+				start--
+			} else if end < len(p) && strings.ContainsRune(".-_", rune(p[end])) {
+				end++
+			}
+			p = p[:start] + p[end:]
+			i = strings.Index(p, "{level}")
+		}
+	}
+	return p
+}
+
+// splitPattern 把文件名格式拆分为主体和扩展名，扩展名只取最后一个占位符之后的固定部分，
+// 因此带点的主机名、应用名或没有扩展名的格式都不会把占位符展开的内容当作扩展名
+func splitPattern(p string) (stem, ext string) {
+	tail := 0
+	if locs := patternTokenRegexp.FindAllStringIndex(p, -1); len(locs) > 0 {
+		tail = locs[len(locs)-1][1]
+	}
+	if i := strings.LastIndexByte(p[tail:], '.'); i >= 0 {
+		ext = p[tail+i:]
+	}
+	return p[:len(p)-len(ext)], ext
+}
+
+// fileBase 返回某个级别在 period 周期内的文件名（不含目录）拆分出的主体和扩展名，调用方需持有 l.mu
+func (l *Logger) fileBase(period, level string) (base, ext string) {
+	stem, ext := splitPattern(l.pattern())
+	base = patternTokenRegexp.ReplaceAllStringFunc(stem, func(token string) string {
+		switch token {
+		case "{app}":
+			return l.appName()
+		case "{hostname}":
+			host, _ := hostname()
+			return host
+		case "{pid}":
+			return pidString()
+		case "{date}":
+			return period
+		case "{level}":
+			return level
+		}
+		return token
+	})
+	return base, ext
+}
+
+// fileRegexp 返回匹配当前文件名格式的正则表达式，包含 date、level、index 三个命名分组，调用方需持有 l.mu
+func (l *Logger) fileRegexp() *regexp.Regexp {
+	stem, ext := splitPattern(l.pattern())
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range patternTokenRegexp.FindAllStringSubmatchIndex(stem, -1) {
+		b.WriteString(regexp.QuoteMeta(stem[last:loc[0]]))
+		switch stem[loc[2]:loc[3]] {
+		case "app":
+			b.WriteString(regexp.QuoteMeta(l.appName()))
+		case "hostname":
+			host, _ := hostname()
+			b.WriteString(regexp.QuoteMeta(host))
+		case "pid":
+			b.WriteString(`\d+`)
+		case "date":
+			if l.getRotationPolicy() != nil {
+				b.WriteString("(?P<date>" + policyPeriodPattern + ")")
+			} else {
+				b.WriteString("(?P<date>" + periodPattern + ")")
+			}
+		case "level":
+			names := make([]string, 0, len(levelTable()))
+			for _, level := range Levels() {
+				names = append(names, regexp.QuoteMeta(level.lowerString()))
+			}
+			b.WriteString("(?P<level>" + strings.Join(names, "|") + ")")
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(stem[last:]))
+	b.WriteString(`(?:\.(?P<index>\d+))?`)
+	b.WriteString(regexp.QuoteMeta(ext))
+	b.WriteString(codecPattern() + "$")
+	return regexp.MustCompile(b.String())
+}
+
+// matchLogFile 判断 name 是否为本包按当前格式生成的日志文件
+func matchLogFile(re *regexp.Regexp, name string) (logFile, bool) {
+	m := re.FindStringSubmatch(name)
+	if m == nil {
+		return logFile{}, false
+	}
+	f := logFile{name: name}
+	if i := re.SubexpIndex("date"); i >= 0 {
+		f.date = m[i]
+	}
+	if i := re.SubexpIndex("level"); i >= 0 {
+		f.level = m[i]
+	}
+	if i := re.SubexpIndex("index"); i >= 0 {
+		f.index, _ = strconv.Atoi(m[i])
+	}
+	return f, true
+}
+
+// programName 返回当前程序的名称
+func programName() string {
+	name := filepath.Base(os.Args[0])
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
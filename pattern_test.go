@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFileRegexpMatchesFileBase(t *testing.T) {
+	defer func(orig func() (string, error)) { hostname = orig }(hostname)
+	hostname = func() (string, error) { return "web-01.example.com", nil }
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		// level 是从文件名中解析出的级别，格式中没有 {level} 时为空
+		level string
+	}{
+		{name: "默认格式", pattern: "", want: "2024-03-09.info.log", level: "info"},
+		{name: "带点的主机名", pattern: "{hostname}-{date}.{level}.log", want: "web-01.example.com-2024-03-09.info.log", level: "info"},
+		{name: "带点的主机名且没有扩展名", pattern: "{hostname}-{date}", want: "web-01.example.com-2024-03-09"},
+		{name: "没有扩展名", pattern: "{level}-{date}", want: "info-2024-03-09", level: "info"},
+		{name: "主机名在最后", pattern: "{date}.{level}.{hostname}", want: "2024-03-09.info.web-01.example.com", level: "info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := New(WithDir(dir))
+			defer l.Close()
+			l.SetFilePattern(tt.pattern)
+			l.mu.Lock()
+			base, ext := l.fileBase("2024-03-09", "info")
+			re := l.fileRegexp()
+			period := l.period
+			l.mu.Unlock()
+			if got := base + ext; got != tt.want {
+				t.Fatalf("fileBase 生成的文件名为 %q，应当为 %q", got, tt.want)
+			}
+			for index, name := range map[int]string{0: base + ext, 2: base + ".2" + ext} {
+				f, ok := matchLogFile(re, name)
+				if !ok {
+					t.Errorf("%s 没有匹配 %s", re, name)
+					continue
+				}
+				if f.date != "2024-03-09" || f.level != tt.level || f.index != index {
+					t.Errorf("%s 解析为 date=%q level=%q index=%d，应当为 date=2024-03-09 level=%s index=%d", name, f.date, f.level, f.index, tt.level, index)
+				}
+			}
+
+			// 实际写入的文件也应当被识别为日志文件
+			l.Info.Println("hello")
+			if err := l.Sync(); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) == 0 {
+				t.Fatal("没有写入日志文件")
+			}
+			for _, entry := range entries {
+				f, ok := matchLogFile(re, entry.Name())
+				if !ok {
+					t.Errorf("%s 没有匹配写入的文件 %s", re, entry.Name())
+					continue
+				}
+				if f.date != period || f.level != tt.level {
+					t.Errorf("%s 解析为 date=%q level=%q，应当为 date=%s level=%s", entry.Name(), f.date, f.level, period, tt.level)
+				}
+			}
+		})
+	}
+}
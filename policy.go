@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RotationPolicy 决定何时切换到新的日志文件，可以使用内置的 DailyRotation、HourlyRotation、WeeklyRotation、
+// MonthlyRotation、SizeRotation、CompositeRotation，也可以自行实现，例如在 UTC 0 点或者文件达到 512MB 时轮转：
+//
+//	logger.SetRotationPolicy(logger.CompositeRotation(
+//		logger.UTCRotation(logger.DailyRotation()),
+//		logger.SizeRotation(512<<20),
+//	))
+type RotationPolicy interface {
+	// ShouldRotate 在每次写入非空的日志文件前调用，size 是写入后文件的大小，返回 true 时切换到带序号的新文件
+	ShouldRotate(now time.Time, size int64) bool
+	// NextName 返回在 now 写入的日志文件名中 {date} 的部分，只能包含字母、数字、- 和 _，
+	// 与正在写入的文件不同时轮转所有级别的文件，返回空字符串时按 SetRotationInterval 的周期命名
+	NextName(now time.Time) string
+}
+
+// SetRotationPolicy 设置默认实例的轮转策略
+func SetRotationPolicy(p RotationPolicy) {
+	std.SetRotationPolicy(p)
+}
+
+// SetRotationPolicy 设置轮转策略，设置后 SetRotationInterval 只在 NextName 返回空字符串时生效，
+// SetMaxFileSize 仍然生效，传入 nil 恢复默认的按天轮转
+func (l *Logger) SetRotationPolicy(p RotationPolicy) {
+	if p == nil {
+		l.rotationPolicy.Store(nil)
+	} else {
+		l.rotationPolicy.Store(&p)
+	}
+	l.rotateIfChanged()
+}
+
+// getRotationPolicy 返回设置的轮转策略，未设置时返回 nil
+func (l *Logger) getRotationPolicy() RotationPolicy {
+	if p := l.rotationPolicy.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// DailyRotation 返回每天 0 点轮转的策略，文件名中的时间部分为 2006-01-02
+func DailyRotation() RotationPolicy {
+	return timeRotation("2006-01-02")
+}
+
+// HourlyRotation 返回每小时轮转的策略，文件名中的时间部分为 2006-01-02_15
+func HourlyRotation() RotationPolicy {
+	return timeRotation("2006-01-02_15")
+}
+
+// WeeklyRotation 返回每周一 0 点轮转的策略，文件名中的时间部分为 ISO 8601 的年和周，例如 2025-W32
+func WeeklyRotation() RotationPolicy {
+	return weeklyRotation{}
+}
+
+// MonthlyRotation 返回每月 1 日 0 点轮转的策略，文件名中的时间部分为 2006-01
+func MonthlyRotation() RotationPolicy {
+	return timeRotation("2006-01")
+}
+
+type weeklyRotation struct{}
+
+func (weeklyRotation) ShouldRotate(now time.Time, size int64) bool {
+	return false
+}
+
+func (weeklyRotation) NextName(now time.Time) string {
+	year, week := now.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// ParseRotationPolicy 将 daily、hourly、weekly、monthly 解析为轮转策略，加上 _utc 后缀时按 UTC 时间命名，例如 weekly_utc，不区分大小写
+func ParseRotationPolicy(s string) (RotationPolicy, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	utc := strings.HasSuffix(name, "_utc")
+	var p RotationPolicy
+	switch strings.TrimSuffix(name, "_utc") {
+	case "daily":
+		p = DailyRotation()
+	case "hourly":
+		p = HourlyRotation()
+	case "weekly":
+		p = WeeklyRotation()
+	case "monthly":
+		p = MonthlyRotation()
+	default:
+		return nil, fmt.Errorf("未知的轮转策略：%q", s)
+	}
+	if utc {
+		p = UTCRotation(p)
+	}
+	return p, nil
+}
+
+// timeRotation 按 now 格式化后的文件名轮转，layout 变化时切换文件
+type timeRotation string
+
+func (r timeRotation) ShouldRotate(now time.Time, size int64) bool {
+	return false
+}
+
+func (r timeRotation) NextName(now time.Time) string {
+	return now.Format(string(r))
+}
+
+// SizeRotation 返回文件超过 bytes 字节时切换到带序号的新文件的策略，文件名按 SetRotationInterval 的周期命名
+func SizeRotation(bytes int64) RotationPolicy {
+	return sizeRotation(bytes)
+}
+
+type sizeRotation int64
+
+func (r sizeRotation) ShouldRotate(now time.Time, size int64) bool {
+	return r > 0 && size > int64(r)
+}
+
+func (r sizeRotation) NextName(now time.Time) string {
+	return ""
+}
+
+// CompositeRotation 返回组合多个策略的策略，任意一个策略需要切换时切换，文件名使用第一个返回非空名称的策略
+func CompositeRotation(policies ...RotationPolicy) RotationPolicy {
+	return compositeRotation(policies)
+}
+
+type compositeRotation []RotationPolicy
+
+func (r compositeRotation) ShouldRotate(now time.Time, size int64) bool {
+	for _, p := range r {
+		if p != nil && p.ShouldRotate(now, size) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r compositeRotation) NextName(now time.Time) string {
+	for _, p := range r {
+		if p == nil {
+			continue
+		}
+		if name := p.NextName(now); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// UTCRotation 返回按 UTC 时间判断和命名的策略，文件名与日志的时间戳无关，时间戳仍然使用 SetTimezone 设置的时区
+func UTCRotation(p RotationPolicy) RotationPolicy {
+	return utcRotation{p}
+}
+
+type utcRotation struct {
+	RotationPolicy
+}
+
+func (r utcRotation) ShouldRotate(now time.Time, size int64) bool {
+	return r.RotationPolicy.ShouldRotate(now.UTC(), size)
+}
+
+func (r utcRotation) NextName(now time.Time) string {
+	return r.RotationPolicy.NextName(now.UTC())
+}
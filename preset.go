@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// PresetContainer 将默认实例配置为适合容器的输出方式
+func PresetContainer() {
+	std.PresetContainer()
+}
+
+// PresetContainer 将日志配置为适合容器的输出方式：JSON 格式、UTC 的 RFC3339 时间戳、只输出到标准输出、不写入日志文件，
+// 由容器运行时收集标准输出，相当于：
+//
+//	l.SetFormat(logger.FormatJSON)
+//	l.SetTimeFormat(time.RFC3339Nano)
+//	l.SetTimezone("UTC")
+//	l.DisableFileOutput()
+//	l.AppendWriter(os.Stdout)
+//
+// 之后仍然可以调用 SetLevel 等方法调整，已经通过 AppendWriter 添加的 os.Stdout 不会重复添加，
+// 但 EnableConsole 添加的控制台输出仍会保留，也可以通过配置项 preset: container 或环境变量 LOGGER_PRESET=container 使用
+func (l *Logger) PresetContainer() {
+	l.SetFormat(FormatJSON)
+	l.SetTimeFormat(time.RFC3339Nano)
+	l.location.Store(time.UTC)
+	l.rotateIfChanged()
+	l.DisableFileOutput()
+	if _, err := l.AppendWriter(os.Stdout); err != nil && !errors.Is(err, ErrDuplicateWriter) {
+		l.reportError(nil, err)
+	}
+}
+
+// applyPreset 应用配置项 preset 指定的预设，目前只有 container
+func (l *Logger) applyPreset(name string) error {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+	case "container":
+		l.PresetContainer()
+	default:
+		return fmt.Errorf("未知的预设：%q", name)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// levelQuota 是一个级别在当前轮转周期内的写入配额
+type levelQuota struct {
+	limit atomic.Int64
+	used  atomic.Int64
+	// exceeded 在超出配额后设置，保证提示只输出一次
+	exceeded atomic.Bool
+}
+
+// SetDailyQuota 设置默认实例中 level 级别每天最多写入的字节数
+func SetDailyQuota(level Level, bytes int64) {
+	std.SetDailyQuota(level, bytes)
+}
+
+// SetDailyQuota 设置 level 级别在每个轮转周期（默认每天）内最多写入的字节数，按编码后的日志计算，
+// 超出后该级别的日志不再写入日志文件和附加的 writer，并在该级别的输出中写入一条提示，直到下一次轮转时重新计算，
+// 用于防止某个级别的大量日志占满磁盘，bytes <= 0 时取消限制，进程重启后从 0 开始计算
+func (l *Logger) SetDailyQuota(level Level, bytes int64) {
+	q := &l.At(level).quota
+	q.limit.Store(bytes)
+	if bytes <= 0 {
+		q.reset()
+	}
+}
+
+func (q *levelQuota) reset() {
+	q.used.Store(0)
+	q.exceeded.Store(false)
+}
+
+// withinQuota 记录写入 n 字节并判断是否在配额内，第一次超出时写入提示
+func (l *logger) withinQuota(n int) bool {
+	limit := l.quota.limit.Load()
+	if limit <= 0 || l.quota.used.Add(int64(n)) <= limit {
+		return true
+	}
+	if l.quota.exceeded.CompareAndSwap(false, true) {
+		l.writeQuotaNotice(limit)
+	}
+	return false
+}
+
+// writeQuotaNotice 在该级别的输出中写入超出配额的提示
+func (l *logger) writeQuotaNotice(limit int64) {
+	msg := "已超出 " + l.level.String() + " 级别的日志配额 " + strconv.FormatInt(limit, 10) + " 字节，下一次轮转前不再写入该级别的日志"
+	l.owner.internalf("%s", msg)
+	b := getBuffer()
+	defer putBuffer(b)
+	encodeEntry(b, &Entry{Time: l.owner.now(), Level: l.level, Message: msg}, l.owner.encodeOptions())
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, _ = l.out.writeBatch(b.Bytes(), nil)
+}
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// RemoteOption 用于 AddRemote 的配置项
+type RemoteOption func(*remoteWriter)
+
+// WithReconnect 设置连接失败或断开后重新连接的间隔，默认为 1 秒
+func WithReconnect(d time.Duration) RemoteOption {
+	return func(w *remoteWriter) {
+		if d > 0 {
+			w.reconnect = d
+		}
+	}
+}
+
+// WithQueue 设置等待发送的日志的最大条数，队列满时丢弃新的日志，默认为 1000
+func WithQueue(size int) RemoteOption {
+	return func(w *remoteWriter) {
+		if size > 0 {
+			w.queue = make(chan []byte, size)
+		}
+	}
+}
+
+// AddRemote 为默认实例添加网络输出
+func AddRemote(network, addr string, opts ...RemoteOption) error {
+	return std.AddRemote(network, addr, opts...)
+}
+
+// AddRemote 将所有级别的日志通过 tcp、udp 或 unix 连接发送到 addr，日志先进入队列再由后台 goroutine 发送，
+// 连接失败或断开时会定时重连，不会阻塞写日志的调用方
+func (l *Logger) AddRemote(network, addr string, opts ...RemoteOption) error {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return fmt.Errorf("不支持的网络类型：%q", network)
+	}
+	w := &remoteWriter{
+		owner:     l,
+		network:   network,
+		addr:      addr,
+		reconnect: time.Second,
+		queue:     make(chan []byte, 1000),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	l.goBackground(w.run)
+	l.AppendWriter(w)
+	return nil
+}
+
+// remoteWriter 将日志放入队列，由 run 在后台发送
+type remoteWriter struct {
+	owner     *Logger
+	network   string
+	addr      string
+	reconnect time.Duration
+	queue     chan []byte
+	// conn 只在 run 所在的 goroutine 中使用
+	conn net.Conn
+}
+
+// remoteTimeout 是连接和单次发送的超时时间
+const remoteTimeout = 5 * time.Second
+
+func (w *remoteWriter) Write(p []byte) (int, error) {
+	line := append([]byte(nil), p...)
+	select {
+	case w.queue <- line:
+	default:
+		w.owner.recordDropped(1)
+	}
+	return len(p), nil
+}
+
+// run 从队列中取出日志发送，发送失败时等待 reconnect 后重试同一条日志，Close 时尽量发送队列中剩余的日志
+func (w *remoteWriter) run() {
+	defer w.closeConn()
+	stop := w.owner.stop
+	for {
+		select {
+		case <-stop:
+			w.drain()
+			return
+		case line := <-w.queue:
+			for !w.send(line) {
+				select {
+				case <-stop:
+					w.owner.recordDropped(1)
+					return
+				case <-time.After(w.reconnect):
+				}
+			}
+		}
+	}
+}
+
+// drain 发送队列中剩余的日志，遇到失败时停止
+func (w *remoteWriter) drain() {
+	for {
+		select {
+		case line := <-w.queue:
+			if !w.send(line) {
+				w.owner.recordDropped(uint64(1 + len(w.queue)))
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// send 在需要时建立连接并发送一条日志，失败时关闭连接并报告错误
+func (w *remoteWriter) send(line []byte) bool {
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.addr, remoteTimeout)
+		if err != nil {
+			w.owner.reportError(w, err)
+			return false
+		}
+		w.conn = conn
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(remoteTimeout))
+	if _, err := w.conn.Write(line); err != nil {
+		w.owner.reportError(w, err)
+		w.closeConn()
+		return false
+	}
+	return true
+}
+
+func (w *remoteWriter) closeConn() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+func (w *remoteWriter) concurrentSafe() {}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+)
+
+// ReopenFiles 重新打开默认实例正在写入的日志文件
+func ReopenFiles() error {
+	return std.ReopenFiles()
+}
+
+// ReopenFiles 重新打开正在写入的日志文件和审计日志文件，用于 logrotate 等外部工具重命名或删除文件之后，
+// 避免继续写入已被删除的文件
+func (l *Logger) ReopenFiles() error {
+	var firstErr error
+	for _, sink := range l.sinks() {
+		if err := sink.reopen(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	// 审计日志从重新打开的文件的最后一行恢复哈希链
+	if err := l.Audit.state.reopen(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// HandleSignal 在默认实例收到 sig 时重新打开日志文件
+func HandleSignal(sig ...os.Signal) {
+	std.HandleSignal(sig...)
+}
+
+// HandleSignal 在收到 sig（通常为 syscall.SIGHUP）时调用 ReopenFiles，直到 Close
+func (l *Logger) HandleSignal(sig ...os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	l.goBackground(func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ch:
+				if err := l.ReopenFiles(); err != nil {
+					l.reportError(nil, err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader 是 HTTPMiddleware 读取和返回请求 ID 使用的请求头，服务之间调用时应原样传递
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLen 是从请求头接受的请求 ID 的最大长度，超过时重新生成
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求 ID 的 context，ctx 中已有请求 ID 时直接返回 ctx，
+// 使用 WithContext(ctx) 输出的日志会带有 request_id 字段
+func WithRequestID(ctx context.Context) context.Context {
+	if RequestIDFrom(ctx) != "" {
+		return ctx
+	}
+	return ContextWithRequestID(ctx, NewRequestID())
+}
+
+// ContextWithRequestID 返回携带指定请求 ID 的 context，用于从消息队列等非 HTTP 的来源传入的 ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return NewContext(ctx, "request_id", id)
+}
+
+// RequestIDFrom 返回 ctx 中的请求 ID，没有时返回空字符串，调用下游服务时可以放入 RequestIDHeader
+func RequestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID 生成一个 32 位十六进制的随机请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// requestID 返回请求头中的请求 ID，没有或者包含不可打印字符时生成新的 ID
+func requestID(r *http.Request) string {
+	id := r.Header.Get(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLen {
+		return NewRequestID()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return NewRequestID()
+		}
+	}
+	return id
+}
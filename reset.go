@@ -0,0 +1,24 @@
+package logger
+
+import "github.com/nickham-su/go_logger/internal/testhook"
+
+func init() {
+	testhook.ResetDefault = resetDefault
+}
+
+// resetDefault 关闭默认实例并以默认配置和 LOGGER_ 环境变量重新创建，同时恢复 Enable 和 SetClockForTesting 的修改，
+// 只能通过 logtest.ResetDefault 在测试中调用，不能与日志输出并发调用，
+// 之前保存的 Trace、Debug 等包级别变量的副本仍指向已关闭的旧实例
+func resetDefault() error {
+	err := std.Close()
+	SetClockForTesting(nil)
+	Enable()
+	std = newDefault()
+	Trace = std.Trace
+	Debug = std.Debug
+	Info = std.Info
+	Warning = std.Warning
+	Error = std.Error
+	Audit = std.Audit
+	return err
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ringBuffer 保存最近的 size 行日志，写满后覆盖最旧的一行
+type ringBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	// next 是下一行写入的位置
+	next int
+	full bool
+}
+
+// EnableRingBuffer 为默认实例开启内存中的最近日志缓存
+func EnableRingBuffer(n int) {
+	std.EnableRingBuffer(n)
+}
+
+// EnableRingBuffer 在内存中保留所有级别最近的 n 行日志，可以通过 DumpRecent 或 RecentHandler 读取，
+// 重新调用会清空已有的日志，n <= 0 时关闭
+func (l *Logger) EnableRingBuffer(n int) {
+	if n <= 0 {
+		l.ring.Store(nil)
+		return
+	}
+	l.ring.Store(&ringBuffer{lines: make([]string, n)})
+}
+
+// DumpRecent 返回默认实例最近的日志
+func DumpRecent() []string {
+	return std.DumpRecent()
+}
+
+// DumpRecent 返回 EnableRingBuffer 保留的最近的日志，按时间从旧到新排列，每行包含末尾的换行，
+// 未开启时返回 nil
+func (l *Logger) DumpRecent() []string {
+	r := l.ring.Load()
+	if r == nil {
+		return nil
+	}
+	return r.dump()
+}
+
+// RecentHandler 返回输出默认实例最近日志的 http.Handler
+func RecentHandler() http.Handler {
+	return std.RecentHandler()
+}
+
+// RecentHandler 返回以纯文本输出最近日志的 http.Handler，可以挂载在 /debug/logs，
+// 参数 n 指定只输出最后 n 行
+func (l *Logger) RecentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lines := l.DumpRecent()
+		if s := r.FormValue("n"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, "参数 n 必须是非负整数", http.StatusBadRequest)
+				return
+			}
+			if n < len(lines) {
+				lines = lines[len(lines)-n:]
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, line := range lines {
+			_, _ = w.Write([]byte(line))
+		}
+	})
+}
+
+// recordRecent 在开启最近日志缓存时保存一行编码后的日志
+func (l *Logger) recordRecent(line []byte) {
+	if r := l.ring.Load(); r != nil {
+		r.add(string(line))
+	}
+}
+
+func (r *ringBuffer) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines[r.next] = line
+	r.next++
+	if r.next == len(r.lines) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (r *ringBuffer) dump() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	lines := make([]string, 0, len(r.lines))
+	lines = append(lines, r.lines[r.next:]...)
+	return append(lines, r.lines[:r.next]...)
+}
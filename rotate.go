@@ -0,0 +1,268 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// SetRotationInterval 设置默认实例的轮转间隔
+func SetRotationInterval(d time.Duration) {
+	std.SetRotationInterval(d)
+}
+
+// SetRotationInterval 设置轮转间隔，间隔从每天 0 点开始计算，不足一天时文件名中带有小时（和分钟），
+// 例如按小时轮转时为 2006-01-02_15.info.log，d <= 0 或不小于一天时按天轮转
+func (l *Logger) SetRotationInterval(d time.Duration) {
+	l.mu.Lock()
+	l.rotationInterval = d
+	l.mu.Unlock()
+	l.rotateIfChanged()
+}
+
+// periodOf 返回 t 所在轮转周期的文件名时间部分，调用方需持有 l.mu
+func (l *Logger) periodOf(t time.Time) string {
+	if p := l.getRotationPolicy(); p != nil {
+		if name := p.NextName(t); name != "" {
+			return name
+		}
+	}
+	d := l.rotationInterval
+	if d <= 0 || d >= 24*time.Hour {
+		return t.Format("2006-01-02")
+	}
+	start, _ := l.periodBounds(t)
+	if d%time.Hour == 0 {
+		return start.Format("2006-01-02_15")
+	}
+	return start.Format("2006-01-02_1504")
+}
+
+// periodBounds 返回 t 所在轮转周期的开始和结束时间，周期不会跨过 0 点，调用方需持有 l.mu
+func (l *Logger) periodBounds(t time.Time) (start, end time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	next := day.AddDate(0, 0, 1)
+	d := l.rotationInterval
+	if d <= 0 || d >= 24*time.Hour {
+		return day, next
+	}
+	start = day.Add(t.Sub(day) / d * d)
+	end = start.Add(d)
+	if end.After(next) {
+		end = next
+	}
+	return start, end
+}
+
+// setPeriod 切换到 t 所在的轮转周期并记录周期的边界，调用方需持有 l.mu
+func (l *Logger) setPeriod(t time.Time) {
+	l.period = l.periodOf(t)
+	period := l.period
+	l.periodName.Store(&period)
+	start, end := l.periodBounds(t)
+	l.periodStart.Store(start.UnixNano())
+	l.periodEnd.Store(end.UnixNano())
+}
+
+// inPeriod 判断 t 是否在当前的轮转周期内，写入时调用，不需要加锁
+func (l *Logger) inPeriod(t time.Time) bool {
+	if p := l.getRotationPolicy(); p != nil {
+		if name := p.NextName(t); name != "" {
+			period := l.periodName.Load()
+			return period != nil && *period == name
+		}
+	}
+	n := t.UnixNano()
+	return n >= l.periodStart.Load() && n < l.periodEnd.Load()
+}
+
+// rotateIfChanged 在当前时间所在的轮转周期变化时轮转，否则只更新周期的边界
+func (l *Logger) rotateIfChanged() {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.mu.Lock()
+	now := l.now()
+	changed := l.period != l.periodOf(now)
+	if !changed {
+		l.setPeriod(now)
+	}
+	l.mu.Unlock()
+	if changed {
+		l.rotateLocked()
+	}
+}
+
+// rotateOnWrite 在写入时间 t 超出当前轮转周期时轮转，Close 之后不再轮转
+func (l *Logger) rotateOnWrite(t time.Time) {
+	if l.inPeriod(t) {
+		return
+	}
+	select {
+	case <-l.stop:
+		return
+	default:
+	}
+	l.rotateIfChanged()
+}
+
+// SetMaxFileSize 设置默认实例单个日志文件的最大字节数
+func SetMaxFileSize(bytes int64) {
+	std.SetMaxFileSize(bytes)
+}
+
+// SetMaxFileSize 设置单个日志文件的最大字节数，超过后切换到带序号的新文件，bytes <= 0 表示不限制
+func (l *Logger) SetMaxFileSize(bytes int64) {
+	l.maxFileSize.Store(bytes)
+}
+
+// cleanup 在按大小切分文件后清理历史文件，与按天轮转互斥
+func (l *Logger) cleanup() {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.removeBackups()
+}
+
+// SetMaxBackups 设置默认实例每个级别保留的历史日志文件数量，n <= 0 表示不限制
+func SetMaxBackups(n int) {
+	std.SetMaxBackups(n)
+}
+
+// SetMaxBackups 设置每个级别保留的历史日志文件数量，n <= 0 表示不限制
+func (l *Logger) SetMaxBackups(n int) {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.mu.Lock()
+	l.maxBackups = n
+	l.mu.Unlock()
+	l.removeBackups()
+}
+
+// SetMaxAge 设置默认实例日志文件的保留天数
+func SetMaxAge(days int) {
+	std.SetMaxAge(days)
+}
+
+// SetMaxAge 设置日志文件的保留天数，包含当天在内超过 days 天的文件会在每天轮转时删除，days <= 0 表示不限制
+func (l *Logger) SetMaxAge(days int) {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+	l.mu.Lock()
+	l.maxAge = days
+	l.mu.Unlock()
+	l.removeBackups()
+}
+
+// removeBackups 删除超过 maxAge 天的日志文件，以及每个级别中超出 maxBackups 的最旧的日志文件，调用方需持有 rotateMu
+func (l *Logger) removeBackups() {
+	l.mu.Lock()
+	dirPath, maxBackups, maxAge := l.dirPath, l.maxBackups, l.maxAge
+	re := l.fileRegexp()
+	l.mu.Unlock()
+	if maxBackups <= 0 && maxAge <= 0 {
+		return
+	}
+	var expired time.Time
+	if maxAge > 0 {
+		expired = l.now().AddDate(0, 0, -maxAge)
+	}
+	active := make(map[string]bool)
+	for _, sink := range l.sinks() {
+		if name := sink.activeName(); name != "" {
+			active[filepath.Clean(name)] = true
+		}
+	}
+	for i, dir := range l.logDirs(dirPath) {
+		removed := l.removeBackupsIn(dir, re, active, maxBackups, expired)
+		// ForKey 的子目录中的文件都已过期时删除空目录，os.Remove 不会删除非空目录
+		if i > 0 && removed {
+			_ = os.Remove(dir)
+		}
+	}
+}
+
+// removeBackupsIn 清理目录 dir 中的日志文件，active 中的文件正在写入，不会被删除，
+// 时间部分不晚于 expired 所在日期的文件会被删除，expired 为零值时不按时间删除，返回是否删除了文件
+func (l *Logger) removeBackupsIn(dirPath string, re *regexp.Regexp, active map[string]bool, maxBackups int, expired time.Time) bool {
+	dir := dirPath
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			l.internalf("清理历史日志文件失败：%v", err)
+		}
+		return false
+	}
+	removed := false
+	backups := make(map[string][]logFile)
+	for _, entry := range entries {
+		if entry.IsDir() || active[filepath.Join(dirPath, entry.Name())] {
+			continue
+		}
+		f, ok := matchLogFile(re, entry.Name())
+		if !ok {
+			continue
+		}
+		if isExpired(f, entry, expired) {
+			l.removeLogFile(filepath.Join(dirPath, f.name))
+			removed = true
+			continue
+		}
+		backups[f.level] = append(backups[f.level], f)
+	}
+	for _, files := range backups {
+		if maxBackups <= 0 || len(files) <= maxBackups {
+			continue
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return newerLogFile(files[i], files[j])
+		})
+		for _, f := range files[maxBackups:] {
+			l.removeLogFile(filepath.Join(dirPath, f.name))
+			removed = true
+		}
+	}
+	return removed
+}
+
+// newerLogFile 判断 a 是否比 b 新，先比较时间部分，同一周期内序号大的文件更新
+func newerLogFile(a, b logFile) bool {
+	if a.date != b.date {
+		return a.date > b.date
+	}
+	return a.index > b.index
+}
+
+// removeLogFile 删除日志文件及其校验文件
+func (l *Logger) removeLogFile(name string) {
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		l.internalf("删除历史日志文件失败：%v", err)
+	}
+	_ = os.Remove(name + checksumExt)
+}
+
+// isExpired 判断日志文件是否过期，文件名中的时间部分不以日期开头时（例如自定义的轮转策略）按修改时间判断
+func isExpired(f logFile, entry os.DirEntry, expired time.Time) bool {
+	if expired.IsZero() || f.date == "" {
+		return false
+	}
+	if datePrefixRegexp.MatchString(f.date) {
+		return f.date[:len("2006-01-02")] <= expired.Format("2006-01-02")
+	}
+	info, err := entry.Info()
+	return err == nil && !info.ModTime().After(expired)
+}
+
+// datePrefixRegexp 匹配以 2006-01-02 形式的日期开头的时间部分
+var datePrefixRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+
+// logFile 是一个由本包生成的日志文件，date 为文件名中的时间部分，合并输出时 level 为空
+type logFile struct {
+	name  string
+	date  string
+	level string
+	index int
+}
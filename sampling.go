@@ -0,0 +1,54 @@
+package logger
+
+import "sync"
+
+// sampler 按秒统计相同级别、相同内容的日志，每秒的前 initial 条全部输出，之后每 thereafter 条输出一条
+type sampler struct {
+	initial    int
+	thereafter int
+	mu         sync.Mutex
+	second     int64
+	counts     map[string]int
+}
+
+// SetSampling 设置默认实例的日志采样
+func SetSampling(initial, thereafter int) {
+	std.SetSampling(initial, thereafter)
+}
+
+// SetSampling 设置日志采样：每秒内相同级别、相同内容的日志只输出前 initial 条，之后每 thereafter 条输出一条，
+// thereafter <= 0 时之后的全部丢弃，initial <= 0 时关闭采样
+func (l *Logger) SetSampling(initial, thereafter int) {
+	if initial <= 0 {
+		l.sampler.Store(nil)
+		return
+	}
+	l.sampler.Store(&sampler{
+		initial:    initial,
+		thereafter: thereafter,
+		counts:     make(map[string]int),
+	})
+}
+
+// allow 判断该日志是否需要输出
+func (s *sampler) allow(e *Entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if second := e.Time.Unix(); second != s.second {
+		s.second = second
+		s.counts = make(map[string]int)
+	}
+	key := e.Level.String() + " " + e.Message
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// sampled 判断该日志在采样后是否需要输出
+func (l *Logger) sampled(e *Entry) bool {
+	s := l.sampler.Load()
+	return s == nil || s.allow(e)
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// shadowWriter 是 SetShadowWriter 设置的影子输出
+type shadowWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc Encoder
+}
+
+// SetShadowWriter 为默认实例设置影子输出
+func SetShadowWriter(w io.Writer, enc Encoder) {
+	std.SetShadowWriter(w, enc)
+}
+
+// SetShadowWriter 将每条日志额外使用 enc 编码后写入 w，日志文件等输出的格式不变，
+// 用于切换格式之前用真实的日志检查新格式的输出，例如 SetShadowWriter(f, JSONEncoder)，
+// 是否带有调用方以及 error 是否单独输出仍由主输出的格式决定，
+// w 或 enc 为 nil 时关闭影子输出，写入 w 失败时调用 SetErrorHandler 设置的回调
+func (l *Logger) SetShadowWriter(w io.Writer, enc Encoder) {
+	if w == nil || enc == nil {
+		l.shadow.Store(nil)
+		return
+	}
+	l.shadow.Store(&shadowWriter{w: w, enc: enc})
+}
+
+// writeShadow 将 e 按影子输出的编码器编码后写入，opts 是主输出使用的配置
+func (l *Logger) writeShadow(e *Entry, opts encodeOptions) {
+	s := l.shadow.Load()
+	if s == nil {
+		return
+	}
+	opts.encoder = s.enc
+	if builtin, ok := s.enc.(builtinEncoder); ok {
+		opts.format = builtin.format
+		opts.encoder = nil
+	}
+	b := getBuffer()
+	defer putBuffer(b)
+	encodeEntry(b, e, opts)
+	s.mu.Lock()
+	_, err := s.w.Write(b.Bytes())
+	s.mu.Unlock()
+	if err != nil {
+		l.reportError(s.w, err)
+	}
+}
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// shardSet 是一个轮转周期内 ForKey 的各个键的日志文件
+type shardSet struct {
+	mu sync.Mutex
+	// sinks 按键和级别保存日志文件，合并输出时同一个键的各级别共用一个 fileSink
+	sinks map[string]map[Level]*fileSink
+}
+
+func newShardSet() *shardSet {
+	return &shardSet{sinks: make(map[string]map[Level]*fileSink)}
+}
+
+// ForKey 返回默认实例中写入键 key 单独日志文件的一组日志记录器
+func ForKey(key string) *contextLogger {
+	return std.ForKey(key)
+}
+
+// ForKey 返回写入键 key 单独日志文件的一组日志记录器，例如每个任务一个日志文件：ForKey("job-42").Info.Println(...)，
+// 文件位于日志目录下以 key 命名的子目录中，文件名格式、轮转、切分、压缩和清理与其他日志文件相同（只处理本进程写入过的键），
+// key 中大写字母、路径分隔符等字符会转义为 %XX，例如 a/b 写入子目录 a%2Fb、Acme 写入 %41cme，
+// 不同的 key 即使只有大小写不同也不会共用目录，
+// 附加的 writer 仍会收到这些日志，不再写入某个键时调用 CloseKey 关闭它的文件
+func (l *Logger) ForKey(key string) *contextLogger {
+	return l.forShard(shardDir(key))
+}
+
+// forShard 返回写入日志目录下子目录 shard 中的文件的一组日志记录器
+func (l *Logger) forShard(shard string) *contextLogger {
+	keyed := func(lv *logger, expandErr bool) *fieldLogger {
+		return &fieldLogger{logger: lv, expandErr: expandErr, shard: shard}
+	}
+	return &contextLogger{
+		Trace:   keyed(l.Trace, false),
+		Debug:   keyed(l.Debug, false),
+		Info:    keyed(l.Info, false),
+		Warning: keyed(l.Warning, false),
+		Error:   keyed(&l.Error.logger, true),
+	}
+}
+
+// CloseKey 关闭默认实例中键 key 的日志文件
+func CloseKey(key string) error {
+	return std.CloseKey(key)
+}
+
+// CloseKey 关闭键 key 已打开的日志文件，之后再写入该键时会重新打开，
+// 用于任务结束后释放文件句柄，否则文件会保持打开直到下一次轮转之后
+func (l *Logger) CloseKey(key string) error {
+	return l.closeShard(shardDir(key))
+}
+
+// closeShard 关闭子目录 shard 中已打开的日志文件
+func (l *Logger) closeShard(shard string) error {
+	l.shardMu.Lock()
+	var sinks map[Level]*fileSink
+	if l.shards != nil {
+		l.shards.mu.Lock()
+		sinks = l.shards.sinks[shard]
+		delete(l.shards.sinks, shard)
+		l.shards.mu.Unlock()
+	}
+	l.shardMu.Unlock()
+	var err error
+	for _, sink := range uniqueSinks(sinks) {
+		if closeErr := sink.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// shardDir 将 key 转换为可以用作目录名的形式，ASCII 小写字母、数字和 - _ . 保持不变，其他字符按 UTF-8 的每个字节转义为 %XX，
+// 转换是一一对应的，不同的 key 不会得到相同的目录名，大写字母和非 ASCII 字符也被转义，
+// 因此在不区分大小写或会规范化 Unicode 的文件系统（Windows、macOS）上，只有大小写不同的 key 也不会共用目录，
+// 空字符串和只由 . 组成的 key 不能用作目录名，转义为 % 和 %2E
+func shardDir(key string) string {
+	if key == "" {
+		return "%"
+	}
+	allDots := strings.Trim(key, ".") == ""
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !allDots && ('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// writeShard 将 lv 级别的日志写入键 shard 的日志文件及附加的 writer
+func (l *Logger) writeShard(lv *logger, shard string, line []byte, ends []int) {
+	l.shardMu.RLock()
+	defer l.shardMu.RUnlock()
+	sink := l.shardSink(l.shards, shard, lv.level)
+	n, err := l.newMultiWriter(l.outputs(lv.level, sink)).writeBatch(line, ends)
+	count := 1
+	if ends != nil {
+		count = len(ends)
+	}
+	l.metrics.recordWrite(&lv.lines, count, n, err)
+}
+
+// shardSink 返回 set 中键 shard 的 level 级别的日志文件，不存在时创建，调用方需持有 shardMu
+func (l *Logger) shardSink(set *shardSet, shard string, level Level) *fileSink {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	sinks := set.sinks[shard]
+	if sink := sinks[level]; sink != nil {
+		return sink
+	}
+	if sinks == nil {
+		sinks = make(map[Level]*fileSink)
+		set.sinks[shard] = sinks
+	}
+	l.mu.Lock()
+	base, ext := l.fileBase(l.period, level.lowerString())
+	path := filepath.Join(l.dirPath, shard, base)
+	if l.shardDirs == nil {
+		l.shardDirs = make(map[string]bool)
+	}
+	l.shardDirs[shard] = true
+	l.mu.Unlock()
+	for _, sink := range sinks {
+		if sink.baseName == path && sink.ext == ext {
+			sinks[level] = sink
+			return sink
+		}
+	}
+	sinks[level] = newFileSink(l, path, ext)
+	return sinks[level]
+}
+
+// rotateShards 在轮转时为已打开的键切换到新周期的日志文件并关闭旧的文件，调用方需持有 rotateMu
+func (l *Logger) rotateShards() {
+	l.shardMu.Lock()
+	old := l.shards
+	l.shards = newShardSet()
+	type rotated struct {
+		old, new *fileSink
+	}
+	var switched []rotated
+	if old != nil {
+		for shard, sinks := range old.sinks {
+			for level, sink := range sinks {
+				// 整个周期都没有写入的键不再保留，避免一直不调用 CloseKey 的键越积越多
+				if sink.activeName() == "" {
+					continue
+				}
+				switched = append(switched, rotated{old: sink, new: l.shardSink(l.shards, shard, level)})
+			}
+		}
+	}
+	l.shardMu.Unlock()
+	closed := make(map[*fileSink]bool)
+	if old != nil {
+		for _, sinks := range old.sinks {
+			for _, sink := range sinks {
+				if sink.activeName() == "" {
+					_ = sink.close()
+				}
+			}
+		}
+	}
+	for _, r := range switched {
+		if closed[r.old] {
+			continue
+		}
+		closed[r.old] = true
+		name := r.old.activeName()
+		_ = r.old.close()
+		if name != "" && name != r.new.pendingName() {
+			l.notifyRotate(name, r.new.pendingName())
+		}
+	}
+}
+
+// shardSinks 返回 ForKey 的各个键当前使用的日志文件
+func (l *Logger) shardSinks() []*fileSink {
+	l.shardMu.RLock()
+	defer l.shardMu.RUnlock()
+	if l.shards == nil {
+		return nil
+	}
+	l.shards.mu.Lock()
+	defer l.shards.mu.Unlock()
+	var sinks []*fileSink
+	for _, levelSinks := range l.shards.sinks {
+		sinks = append(sinks, uniqueSinks(levelSinks)...)
+	}
+	return sinks
+}
+
+// uniqueSinks 返回 sinks 中不重复的日志文件
+func uniqueSinks(sinks map[Level]*fileSink) []*fileSink {
+	var out []*fileSink
+	seen := make(map[*fileSink]bool)
+	for _, sink := range sinks {
+		if !seen[sink] {
+			seen[sink] = true
+			out = append(out, sink)
+		}
+	}
+	return out
+}
+
+// logDirs 返回需要清理和压缩的目录：日志目录及其中 ForKey 和 ForTenant 在本进程中写入过的子目录，
+// 不会处理日志目录下的其他目录，进程重启后之前的键在再次写入之前不会被清理
+func (l *Logger) logDirs(dirPath string) []string {
+	l.mu.Lock()
+	shards := make([]string, 0, len(l.shardDirs))
+	for shard := range l.shardDirs {
+		shards = append(shards, shard)
+	}
+	l.mu.Unlock()
+	sort.Strings(shards)
+	dirs := []string{dirPath}
+	for _, shard := range shards {
+		dirs = append(dirs, filepath.Join(dirPath, shard))
+	}
+	return dirs
+}
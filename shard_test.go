@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShardDir(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"job-42", "job-42"},
+		{"a/b", "a%2Fb"},
+		{"Acme", "%41cme"},
+		{"50%", "50%25"},
+		{"", "%"},
+		{"..", "%2E%2E"},
+		{"@tenants", "%40tenants"},
+		{"租户", "%E7%A7%9F%E6%88%B7"},
+	}
+	for _, tt := range tests {
+		if got := shardDir(tt.key); got != tt.want {
+			t.Errorf("shardDir(%q) = %q，应当为 %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestShardDirCaseInsensitive 检查在不区分大小写、会规范化 Unicode 的文件系统上不同的 key 仍然对应不同的目录，
+// 包括 NFC 和 NFD 形式的 é 以及与 k 大小写折叠相同的开尔文符号
+func TestShardDirCaseInsensitive(t *testing.T) {
+	keys := []string{"acme", "Acme", "ACME", "aCmE", "\u00e9", "e\u0301", "\u00c9", "k", "\u212a"}
+	seen := make(map[string]string)
+	for _, key := range keys {
+		dir := strings.ToLower(shardDir(key))
+		if other, ok := seen[dir]; ok {
+			t.Errorf("%q 和 %q 在不区分大小写的文件系统上使用同一个目录 %s", key, other, shardDir(key))
+		}
+		seen[dir] = key
+	}
+}
+
+func TestForKeyCaseOnlyDifference(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithDir(dir))
+	defer l.Close()
+	l.ForKey("Acme").Info.Println("upper")
+	l.ForKey("acme").Info.Println("lower")
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	name := l.now().Format("2006-01-02") + ".info.log"
+	for sub, want := range map[string]string{"%41cme": "upper", "acme": "lower"} {
+		data, err := os.ReadFile(filepath.Join(dir, sub, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); !strings.Contains(got, want) || strings.Count(got, "\n") != 1 {
+			t.Errorf("%s 中的日志为 %q，应当只有 %q", sub, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// NewSlogHandler 返回写入默认实例的 slog.Handler
+func NewSlogHandler() slog.Handler {
+	return std.SlogHandler()
+}
+
+// SlogHandler 返回一个 slog.Handler，日志按级别写入该实例的文件和 writer
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{owner: l}
+}
+
+// slogHandler 将 slog 的级别映射为 rank 不高于它的最高的级别，例如 slog.LevelDebug-4 对应 Trace，
+// 低于所有级别时使用最低的级别
+type slogHandler struct {
+	owner  *Logger
+	fields []Field
+	// group 是 WithGroup 累积的 key 前缀，例如 "req."
+	group string
+}
+
+func (h *slogHandler) levelLogger(level slog.Level) *logger {
+	levels := h.owner.levels()
+	lv := levels[0]
+	for _, l := range levels[1:] {
+		if l.rank > int(level) {
+			break
+		}
+		lv = l
+	}
+	return lv
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.levelLogger(level).enabled()
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	lv := h.levelLogger(r.Level)
+	if !lv.enabled() {
+		return nil
+	}
+	fields := h.fields[:len(h.fields):len(h.fields)]
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.group, a)
+		return true
+	})
+	t := r.Time
+	if t.IsZero() {
+		t = h.owner.now()
+	} else if loc := h.owner.location.Load(); loc != nil {
+		t = t.In(loc)
+	}
+	e := &Entry{
+		Time:    t,
+		Level:   lv.level,
+		Message: r.Message,
+		Fields:  fields,
+		Context: ctx,
+	}
+	if h.owner.needCaller() && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Caller = formatFrame(frame)
+	}
+	if h.owner.needStack(lv.rank) {
+		e.Stack = stack()
+	}
+	h.owner.decorate(e, "")
+	lv.log(e)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := h.fields[:len(h.fields):len(h.fields)]
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.group, a)
+	}
+	return &slogHandler{owner: h.owner, fields: fields, group: h.group}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{owner: h.owner, fields: h.fields, group: h.group + name + "."}
+}
+
+// appendAttr 将 slog.Attr 展开为字段，分组中的 key 以 . 连接
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
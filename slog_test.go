@@ -0,0 +1,53 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// newSlogTestLogger 返回不写入文件的实例和记录其日志的 Hook
+func newSlogTestLogger(t *testing.T) (*Logger, *entryRecorder) {
+	l := New(WithDir(t.TempDir()))
+	l.DisableFileOutput()
+	r := &entryRecorder{}
+	l.AddHook(r)
+	t.Cleanup(func() { _ = l.Close() })
+	return l, r
+}
+
+func TestSlogHandlerStacktrace(t *testing.T) {
+	l, r := newSlogTestLogger(t)
+	l.SetStacktraceLevel(LevelError)
+	slog.New(l.SlogHandler()).Error("boom")
+	if e := r.last(t); e.Stack == "" {
+		t.Error("ERROR 级别的日志应当附加调用栈")
+	}
+	slog.New(l.SlogHandler()).Info("ok")
+	if e := r.last(t); e.Stack != "" {
+		t.Errorf("INFO 级别的日志不应当附加调用栈，得到 %q", e.Stack)
+	}
+}
+
+func TestSlogHandlerFingerprint(t *testing.T) {
+	l, r := newSlogTestLogger(t)
+	l.SetFingerprint(true)
+	slog.New(l.SlogHandler()).Error("query failed", "table", "orders")
+	if _, ok := fieldValueOf(r.last(t), FingerprintKey); !ok {
+		t.Error("ERROR 级别的日志应当带有 fingerprint 字段")
+	}
+}
+
+func TestSlogHandlerGoroutineID(t *testing.T) {
+	l, r := newSlogTestLogger(t)
+	l.SetGoroutineID(true)
+	slog.New(l.SlogHandler()).Info("started")
+	id, ok := goroutineID()
+	if !ok {
+		t.Fatal("无法获取当前 goroutine 的 ID")
+	}
+	if v, _ := fieldValueOf(r.last(t), GoroutineKey); v != id {
+		t.Errorf("goroutine 字段为 %v，应当为 %d", v, id)
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PrintlnWithStack 输出错误日志并附加调用方的调用栈
+func (l *errorLogger) PrintlnWithStack(v ...interface{}) {
+	if !l.enabled() {
+		return
+	}
+	msg, err := l.sprintln(v, true)
+	l.output(nil, msg, err, stack())
+}
+
+// SetStacktraceLevel 设置默认实例自动附加调用栈的级别
+func SetStacktraceLevel(level Level) {
+	std.SetStacktraceLevel(level)
+}
+
+// SetStacktraceLevel 设置自动附加调用栈的级别，该级别及以上的日志都会附加输出日志处的调用栈，
+// 例如 SetStacktraceLevel(LevelError)
+func (l *Logger) SetStacktraceLevel(level Level) {
+	l.stacktraceLevel.Store(&level)
+}
+
+// DisableStacktrace 关闭默认实例自动附加的调用栈
+func DisableStacktrace() {
+	std.DisableStacktrace()
+}
+
+// DisableStacktrace 关闭 SetStacktraceLevel 设置的自动附加调用栈
+func (l *Logger) DisableStacktrace() {
+	l.stacktraceLevel.Store(nil)
+}
+
+// needStack 判断 rank 级别的日志是否需要自动附加调用栈
+func (l *Logger) needStack(rank int) bool {
+	level := l.stacktraceLevel.Load()
+	return level != nil && rank >= level.Rank()
+}
+
+// RecoverAndLog 用于 defer，将默认实例中捕获的 panic 记录为错误日志
+func RecoverAndLog(repanic bool) {
+	if r := recover(); r != nil {
+		std.logPanic(r, repanic)
+	}
+}
+
+// RecoverAndLog 必须直接用于 defer，例如 defer l.RecoverAndLog(false)，捕获 panic 后以 ERROR 级别
+// 输出 panic 的值和发生 panic 处的调用栈，repanic 为 true 时刷新所有 writer 后再次 panic
+func (l *Logger) RecoverAndLog(repanic bool) {
+	if r := recover(); r != nil {
+		l.logPanic(r, repanic)
+	}
+}
+
+func (l *Logger) logPanic(r interface{}, repanic bool) {
+	lv := &l.Error.logger
+	if lv.enabled() {
+		err, _ := r.(error)
+		lv.output(nil, fmt.Sprint("panic: ", r), err, stack())
+	}
+	if repanic {
+		_ = l.Sync()
+		panic(r)
+	}
+}
+
+// stack 返回调用方的调用栈，跳过最上面属于本包和 runtime 的栈帧，因此 panic 时从发生 panic 的函数开始，
+// 格式与 runtime/debug.Stack 中的栈帧相同
+func stack() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	return formatStack(runtime.CallersFrames(pcs[:n]))
+}
+
+// formatStack 格式化 frames，跳过最上面属于本包、runtime 和 log/slog 的栈帧
+func formatStack(frames *runtime.Frames) string {
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		leading := b.Len() == 0 &&
+			(strings.HasPrefix(frame.Function, pkgPath+".") || strings.HasPrefix(frame.Function, "runtime.") ||
+				strings.HasPrefix(frame.Function, "log/slog."))
+		if !leading {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,40 @@
+package logger
+
+// WriteStartupMarker 在默认实例的日志文件中写入进程启动的标记
+func WriteStartupMarker() {
+	std.WriteStartupMarker()
+}
+
+// WriteStartupMarker 在当前输出的每个级别的日志文件中写入一条 INFO 级别的进程启动标记，例如
+// === process start pid=1234 version=v1.2.0 revision=3f2a9c1 ===，
+// 用于在进程崩溃后反复重启时区分同一个文件中每次启动之后的日志，应当在完成 SetDir、SetFormat 等配置后调用一次，
+// 也可以通过配置项 startup_marker: true 或环境变量 LOGGER_STARTUP_MARKER=true 在应用配置时写入，
+// 标记只写入日志文件，不经过 Hook 和附加的 writer，日志文件总是以追加方式打开，
+// 重启后继续写入当天最新的文件，开启按大小切分时也不会截断或覆盖已有的文件
+func (l *Logger) WriteStartupMarker() {
+	if l.noFileOutput.Load() {
+		return
+	}
+	e := &Entry{
+		Time:    l.now(),
+		Level:   LevelInfo,
+		Message: "=== process start pid=" + pidString() + buildVersion() + " ===",
+	}
+	b := getBuffer()
+	defer putBuffer(b)
+	encodeEntry(b, e, l.encodeOptions())
+	seen := make(map[*fileSink]bool)
+	for _, lv := range l.levels() {
+		if !lv.enabled() {
+			continue
+		}
+		lv.mu.RLock()
+		if sink := lv.sink; sink != nil && !seen[sink] {
+			seen[sink] = true
+			if _, err := sink.Write(b.Bytes()); err != nil {
+				l.reportError(sink, err)
+			}
+		}
+		lv.mu.RUnlock()
+	}
+}
@@ -0,0 +1,33 @@
+package logger
+
+import "log"
+
+// StdLogger 返回将日志以 level 级别写入默认实例的 *log.Logger
+func StdLogger(level Level) *log.Logger {
+	return std.StdLogger(level)
+}
+
+// StdLogger 返回将日志以 level 级别写入的 *log.Logger，时间戳等由本包添加，
+// 可以传给只接受标准库 *log.Logger 的代码，例如 http.Server.ErrorLog
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(l.At(level).Writer(), "", 0)
+}
+
+// RedirectStdLog 将标准库 log 包的默认输出重定向到默认实例的 Info 级别
+func RedirectStdLog() func() {
+	return std.RedirectStdLog()
+}
+
+// RedirectStdLog 将标准库 log 包的默认输出（包括依赖中的 log.Printf）重定向到 Info 级别，
+// 返回的函数用于恢复原来的输出、前缀和 flags
+func (l *Logger) RedirectStdLog() func() {
+	w, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	log.SetOutput(l.Info.Writer())
+	log.SetPrefix("")
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(w)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}
+}
@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// streamPingInterval 是 StreamHandler 在没有日志时发送心跳的间隔，避免代理关闭空闲的连接
+const streamPingInterval = 15 * time.Second
+
+// websocketGUID 用于计算 WebSocket 握手的 Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// StreamHandler 返回实时推送默认实例日志的 http.Handler
+func StreamHandler() http.Handler {
+	return std.StreamHandler()
+}
+
+// StreamHandler 返回实时推送日志的 http.Handler，可以挂载在 /debug/logs/stream，
+// 请求带有 Upgrade: websocket 时升级为 WebSocket，每条日志为一条文本消息，否则以 Server-Sent Events 推送，每条日志为一个 data 事件，
+// 日志编码为一行 JSON，参数 level 指定最低级别，例如 ?level=warning，默认推送所有级别，
+// 客户端接收不及时时丢弃日志，WebSocket 只接受同源的连接，日志可能包含敏感信息，应当在鉴权之后挂载
+func (l *Logger) StreamHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level := LevelTrace
+		if s := r.FormValue("level"); s != "" {
+			var err error
+			if level, err = ParseLevel(s); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			l.streamWebSocket(w, r, level)
+			return
+		}
+		l.streamSSE(w, r, level)
+	})
+}
+
+// streamEncode 将 e 编码为一行不含换行的 JSON
+func (l *Logger) streamEncode(b *bytes.Buffer, e *Entry) []byte {
+	opts := l.encodeOptions()
+	opts.format = FormatJSON
+	opts.encoder = nil
+	b.Reset()
+	encodeJSON(b, e, opts)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+func (l *Logger) streamSSE(w http.ResponseWriter, r *http.Request, level Level) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "不支持流式响应", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	entries := l.Follow(r.Context(), level)
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+	var b bytes.Buffer
+	for {
+		select {
+		case e, ok := <-entries:
+			if !ok {
+				return
+			}
+			line := l.streamEncode(&b, &e)
+			if _, err := io.WriteString(w, "data: "+string(line)+"\n\n"); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (l *Logger) streamWebSocket(w http.ResponseWriter, r *http.Request, level Level) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "WebSocket 握手请求错误", http.StatusBadRequest)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "不允许跨域的 WebSocket 连接", http.StatusForbidden)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "不支持 WebSocket", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if rw.Flush() != nil {
+		return
+	}
+	// 客户端关闭连接或发送关闭帧时 done 被关闭，停止推送
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		readWebSocket(rw.Reader)
+	}()
+	// 处理函数返回后 r.Context() 结束，Follow 的通道随之关闭
+	entries := l.Follow(r.Context(), level)
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+	var b bytes.Buffer
+	for {
+		var err error
+		select {
+		case e, ok := <-entries:
+			if !ok {
+				return
+			}
+			err = writeWebSocketFrame(conn, 0x1, l.streamEncode(&b, &e))
+		case <-ticker.C:
+			err = writeWebSocketFrame(conn, 0x9, nil)
+		case <-done:
+			_ = writeWebSocketFrame(conn, 0x8, nil)
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sameOrigin 判断 WebSocket 请求的 Origin 是否与请求的 Host 相同，没有 Origin 时（非浏览器客户端）允许
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// writeWebSocketFrame 写入一个不分片、不带掩码的服务端帧
+func writeWebSocketFrame(conn net.Conn, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xffff:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(streamPingInterval))
+	_, err := (&net.Buffers{header, payload}).WriteTo(conn)
+	return err
+}
+
+// readWebSocket 读取并丢弃客户端发送的帧，直到收到关闭帧或连接出错
+func readWebSocket(r *bufio.Reader) {
+	var header [2]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return
+		}
+		opcode := header[0] & 0x0f
+		n := uint64(header[1] & 0x7f)
+		switch n {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return
+			}
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return
+			}
+			n = binary.BigEndian.Uint64(ext[:])
+		}
+		if header[1]&0x80 != 0 {
+			n += 4 // 客户端的帧带有 4 字节的掩码
+		}
+		if opcode == 0x8 {
+			return
+		}
+		if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// SyncPolicy 决定何时将日志文件同步（fsync）到磁盘
+type SyncPolicy struct {
+	everyWrite bool
+	interval   time.Duration
+}
+
+var (
+	// SyncNever 不主动同步，由操作系统决定何时落盘，只在 Sync 和 Close 时同步，是默认的策略
+	SyncNever = SyncPolicy{}
+	// SyncEveryWrite 每写入一条日志都同步一次，断电时不会丢失已输出的日志，但吞吐量最低
+	SyncEveryWrite = SyncPolicy{everyWrite: true}
+)
+
+// SyncInterval 返回每隔 d 同步一次所有日志文件的策略，d <= 0 时等同于 SyncNever
+func SyncInterval(d time.Duration) SyncPolicy {
+	return SyncPolicy{interval: d}
+}
+
+// syncer 是定时同步或刷新日志文件的后台 goroutine
+type syncer struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// SetSyncPolicy 设置默认实例的同步策略
+func SetSyncPolicy(policy SyncPolicy) {
+	std.SetSyncPolicy(policy)
+}
+
+// SetSyncPolicy 设置日志文件的同步策略，只作用于日志文件，附加的 writer 仍需通过 Sync 刷新
+func (l *Logger) SetSyncPolicy(policy SyncPolicy) {
+	l.syncEveryWrite.Store(policy.everyWrite)
+	var s *syncer
+	if policy.interval > 0 {
+		s = l.startSyncer(policy.interval, func(sink *fileSink) error {
+			return sink.sync()
+		})
+	}
+	if old := l.syncer.Swap(s); old != nil {
+		old.stop()
+	}
+}
+
+// startSyncer 启动每隔 interval 对所有日志文件调用 f 的 goroutine
+func (l *Logger) startSyncer(interval time.Duration, f func(sink *fileSink) error) *syncer {
+	s := &syncer{done: make(chan struct{})}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, sink := range l.sinks() {
+					if err := f(sink); err != nil {
+						l.reportError(nil, err)
+					}
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *syncer) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
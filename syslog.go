@@ -0,0 +1,53 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+	"strings"
+)
+
+// EnableSyslog 为默认实例添加 syslog 输出
+func EnableSyslog(network, addr, tag string) error {
+	return std.EnableSyslog(network, addr, tag)
+}
+
+// EnableSyslog 连接 syslog 并将各级别的日志以对应的严重级别发送，
+// network 和 addr 为空时连接本机的 syslog 服务
+func (l *Logger) EnableSyslog(network, addr, tag string) error {
+	w, err := syslog.Dial(network, addr, syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return err
+	}
+	for _, level := range Levels() {
+		l.AppendWriterFor(level, syslogWriter{writer: w, level: level})
+	}
+	return nil
+}
+
+// syslogWriter 将一个级别的日志以对应的 syslog 严重级别写入
+type syslogWriter struct {
+	writer *syslog.Writer
+	level  Level
+}
+
+func (w syslogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	var err error
+	switch rank := w.level.Rank(); {
+	case rank < LevelInfo.Rank():
+		err = w.writer.Debug(msg)
+	case rank < LevelWarning.Rank():
+		err = w.writer.Info(msg)
+	case rank < LevelError.Rank():
+		err = w.writer.Warning(msg)
+	default:
+		err = w.writer.Err(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w syslogWriter) concurrentSafe() {}
@@ -0,0 +1,345 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoLogSource 是 Tail 既没有写入日志文件也没有开启 EnableRingBuffer 时返回的错误
+var ErrNoLogSource = errors.New("没有可以读取的日志：未写入日志文件且未开启 EnableRingBuffer")
+
+// errUnreadableFormat 是使用无法解析的输出格式时 Tail 返回的错误
+var errUnreadableFormat = errors.New("无法读取自定义编码器或 MessagePack 格式的日志")
+
+// tailChunk 是从文件末尾向前每次读取的字节数
+const tailChunk = 64 << 10
+
+// followBuffer 是 Follow 返回的通道的缓冲大小
+const followBuffer = 256
+
+// Tail 返回默认实例最近的 n 条日志
+func Tail(level Level, n int) ([]Entry, error) {
+	return std.Tail(level, n)
+}
+
+// Tail 返回 level 及以上级别最近的 n 条日志，按时间从旧到新排列，用于在管理页面中展示最近的日志，
+// 写入日志文件时从各级别当前的日志文件末尾读取，不足 n 条时继续读取之前切分的文件和历史文件，关闭文件输出时从 EnableRingBuffer 保留的最近日志中读取，
+// 文本格式中的字段和调用方保留在 Message 中，跨行的调用栈记录在 Stack 中，使用 SetEncoder 设置的自定义编码器时返回错误
+func (l *Logger) Tail(level Level, n int) ([]Entry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	p, err := l.newEntryParser()
+	if err != nil {
+		return nil, err
+	}
+	rank := level.Rank()
+	var entries []Entry
+	switch {
+	case !l.noFileOutput.Load():
+		l.Flush()
+		seen := make(map[*fileSink]bool)
+		for _, lv := range l.levels() {
+			lv.mu.RLock()
+			sink := lv.sink
+			lv.mu.RUnlock()
+			if lv.rank < rank || sink == nil || seen[sink] {
+				continue
+			}
+			seen[sink] = true
+			if err := sink.flush(); err != nil {
+				return nil, err
+			}
+			name := sink.activeName()
+			if name == "" {
+				continue
+			}
+			found, err := tailFile(name, n, p, rank, l.encryption.Load())
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			if len(found) < n {
+				for _, prev := range l.previousLogFiles(name) {
+					more, err := tailFile(prev, n-len(found), p, rank, l.encryption.Load())
+					if err != nil && !os.IsNotExist(err) {
+						return nil, err
+					}
+					found = append(more, found...)
+					if len(found) >= n {
+						break
+					}
+				}
+			}
+			entries = append(entries, found...)
+		}
+	case l.ring.Load() != nil:
+		for _, line := range l.ring.Load().dump() {
+			entries = append(entries, filterRank(p.parse([]byte(line)), rank)...)
+		}
+	default:
+		return nil, ErrNoLogSource
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// tailFile 从文件 name 的末尾读取 rank 及以上级别的最后 n 条日志，
+// aead 不为 nil 或者文件是流式压缩的文件时从头解密、解压，只保留最后 n 条日志
+func tailFile(name string, n int, p *entryParser, rank int, aead *cipher.AEAD) ([]Entry, error) {
+	if aead != nil || codecFor(name) != nil {
+		f, err := OpenLogFile(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if aead != nil {
+			if r, err = newDecryptReader(f, *aead); err != nil {
+				return nil, err
+			}
+		}
+		return tailStream(r, n, p, rank)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return tailReaderAt(f, info.Size(), n, p, rank)
+}
+
+// tailReaderAt 从 r 的末尾每次向前读取 tailChunk 字节，直到找到 n 条日志或读到开头
+func tailReaderAt(r io.ReaderAt, size int64, n int, p *entryParser, rank int) ([]Entry, error) {
+	var entries []Entry
+	// carry 是后一块中第一条日志之前的部分，可能是不完整的一行或属于前一块中最后一条日志的调用栈
+	var carry []byte
+	for end := size; end > 0 && len(entries) < n; {
+		start := end - tailChunk
+		if start < 0 {
+			start = 0
+		}
+		buf := make([]byte, end-start, end-start+int64(len(carry)))
+		if _, err := r.ReadAt(buf, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		buf = append(buf, carry...)
+		end = start
+		head := 0
+		if start > 0 {
+			if head = firstEntryLine(buf, p); head < 0 {
+				carry = buf
+				continue
+			}
+		}
+		carry = buf[:head]
+		entries = append(filterRank(p.parse(buf[head:]), rank), entries...)
+		if len(entries) > n {
+			entries = entries[len(entries)-n:]
+		}
+	}
+	return entries, nil
+}
+
+// firstEntryLine 返回 data 中第一行之后第一条日志开头的位置，第一行可能不完整，没有时返回 -1
+func firstEntryLine(data []byte, p *entryParser) int {
+	i := bytes.IndexByte(data, '\n')
+	for i >= 0 {
+		start := i + 1
+		line := data[start:]
+		if j := bytes.IndexByte(line, '\n'); j >= 0 {
+			line = line[:j]
+			i = start + j
+		} else {
+			i = -1
+		}
+		if _, ok := p.parseLine(string(line)); ok {
+			return start
+		}
+	}
+	return -1
+}
+
+// tailStream 逐行读取 r，只保留 rank 及以上级别的最后 n 条日志
+func tailStream(r io.Reader, n int, p *entryParser, rank int) ([]Entry, error) {
+	br := bufio.NewReader(r)
+	var entries []Entry
+	var cur *Entry
+	keep := func() {
+		if cur == nil || cur.Level.Rank() < rank {
+			return
+		}
+		entries = append(entries, *cur)
+		if len(entries) >= 2*n {
+			entries = append(entries[:0], entries[len(entries)-n:]...)
+		}
+	}
+	for {
+		line, err := br.ReadString('\n')
+		line = strings.TrimSuffix(line, "\n")
+		if e, ok := p.parseLine(line); ok {
+			keep()
+			cur = &e
+		} else if cur != nil && line != "" && p.continues(line) {
+			cur.appendStack(line)
+		}
+		// 正在写入的压缩文件没有结束标记，读到末尾时返回 io.ErrUnexpectedEOF
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	keep()
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// previousLogFiles 返回与正在写入的文件 name 属于同一级别、更早的切分文件和历史文件，按从新到旧排列
+func (l *Logger) previousLogFiles(name string) []string {
+	l.mu.Lock()
+	re := l.fileRegexp()
+	l.mu.Unlock()
+	dir, base := filepath.Split(name)
+	current, ok := matchLogFile(re, base)
+	if !ok {
+		return nil
+	}
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	var files []logFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		f, ok := matchLogFile(re, entry.Name())
+		if ok && f.level == current.level && newerLogFile(current, f) {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return newerLogFile(files[i], files[j])
+	})
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = filepath.Join(dir, f.name)
+	}
+	return names
+}
+
+// filterRank 返回 entries 中 rank 及以上级别的日志
+func filterRank(entries []Entry, rank int) []Entry {
+	out := entries[:0]
+	for _, e := range entries {
+		if e.Level.Rank() >= rank {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// follower 是 Follow 的一个订阅者
+type follower struct {
+	mu     sync.Mutex
+	ch     chan Entry
+	rank   int
+	closed bool
+}
+
+// Follow 返回接收默认实例之后输出的日志的通道
+func Follow(ctx context.Context, level Level) <-chan Entry {
+	return std.Follow(ctx, level)
+}
+
+// Follow 返回接收之后输出的 level 及以上级别日志的通道，用于在管理页面中实时展示日志，ctx 结束后通道被关闭，
+// 日志在 Hook 之后、编码之前发送，接收不及时导致通道的缓冲已满时丢弃新的日志，不会阻塞写入
+func (l *Logger) Follow(ctx context.Context, level Level) <-chan Entry {
+	f := &follower{ch: make(chan Entry, followBuffer), rank: level.Rank()}
+	l.mu.Lock()
+	var followers []*follower
+	if old := l.followers.Load(); old != nil {
+		followers = append(followers, *old...)
+	}
+	followers = append(followers, f)
+	l.followers.Store(&followers)
+	l.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		l.removeFollower(f)
+		f.mu.Lock()
+		f.closed = true
+		close(f.ch)
+		f.mu.Unlock()
+	}()
+	return f.ch
+}
+
+func (l *Logger) removeFollower(f *follower) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	old := l.followers.Load()
+	if old == nil {
+		return
+	}
+	var followers []*follower
+	for _, other := range *old {
+		if other != f {
+			followers = append(followers, other)
+		}
+	}
+	if len(followers) == 0 {
+		l.followers.Store(nil)
+		return
+	}
+	l.followers.Store(&followers)
+}
+
+// notifyFollowers 将 e 的副本发送给 Follow 的订阅者
+func (l *Logger) notifyFollowers(e *Entry) {
+	followers := l.followers.Load()
+	if followers == nil {
+		return
+	}
+	rank := e.Level.Rank()
+	for _, f := range *followers {
+		if rank < f.rank {
+			continue
+		}
+		c := *e
+		c.Fields = append([]Field(nil), e.Fields...)
+		f.mu.Lock()
+		if !f.closed {
+			select {
+			case f.ch <- c:
+			default:
+			}
+		}
+		f.mu.Unlock()
+	}
+}
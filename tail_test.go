@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// assertTailMessages 检查 entries 依次为 prefix first 到 prefix first+len(entries)-1 的日志
+func assertTailMessages(t *testing.T, entries []Entry, n int, prefix string, first int) {
+	t.Helper()
+	if len(entries) != n {
+		t.Fatalf("得到 %d 条日志，应当为 %d 条", len(entries), n)
+	}
+	for i, e := range entries {
+		if want := fmt.Sprintf("%s %d", prefix, first+i); !strings.HasPrefix(e.Message, want) {
+			t.Fatalf("第 %d 条日志为 %q，应当为 %q", i, e.Message, want)
+		}
+	}
+}
+
+func TestTailReadsAcrossChunks(t *testing.T) {
+	l := New(WithDir(t.TempDir()))
+	defer l.Close()
+	// 每条日志带有一行调用栈，共约 10 个 tailChunk，调用栈会落在块的边界上
+	total := 10 * tailChunk / 60
+	for i := 0; i < total; i++ {
+		l.Error.Printf("message %d\n\tat frame %d", i, i)
+	}
+	n := total / 2
+	entries, err := l.Tail(LevelError, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTailMessages(t, entries, n, "message", total-n)
+	for i, e := range entries {
+		if want := fmt.Sprintf("at frame %d", total-n+i); !strings.Contains(e.Stack, want) {
+			t.Fatalf("第 %d 条日志的调用栈为 %q，应当包含 %q", i, e.Stack, want)
+		}
+	}
+}
+
+func TestTailFallsBackToPreviousFiles(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compressed=%v", compressed), func(t *testing.T) {
+			l := New(WithDir(t.TempDir()))
+			defer l.Close()
+			l.SetStreamCompression(compressed)
+			l.SetMaxFileSize(4 << 10)
+			for i := 0; i < 500; i++ {
+				l.Info.Println("message", i)
+			}
+			entries, err := l.Tail(LevelInfo, 300)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertTailMessages(t, entries, 300, "message", 200)
+		})
+	}
+}
+
+func TestTailStreamKeepsLastEntries(t *testing.T) {
+	l := New(WithDir(t.TempDir()))
+	defer l.Close()
+	p, err := l.newEntryParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out syncBuffer
+	if _, err := l.AppendWriter(&out); err != nil {
+		t.Fatal(err)
+	}
+	l.DisableFileOutput()
+	for i := 0; i < 100; i++ {
+		lv := l.Info
+		if i%2 == 1 {
+			lv = &l.Error.logger
+		}
+		lv.Printf("message %d\n\tat frame %d", i, i)
+	}
+	entries, err := tailStream(strings.NewReader(out.String()), 10, p, LevelError.Rank())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 10 {
+		t.Fatalf("得到 %d 条日志，应当为 10 条", len(entries))
+	}
+	for i, e := range entries {
+		want := 81 + 2*i
+		if !strings.HasPrefix(e.Message, fmt.Sprintf("message %d", want)) || !strings.Contains(e.Stack, fmt.Sprintf("at frame %d", want)) {
+			t.Fatalf("第 %d 条日志为 %q，调用栈为 %q，应当为 message %d", i, e.Message, e.Stack, want)
+		}
+	}
+}
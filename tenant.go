@@ -0,0 +1,37 @@
+package logger
+
+import "path/filepath"
+
+// TenantKey 是 ForTenant 附加的租户字段名
+const TenantKey = "tenant"
+
+// tenantDir 是日志目录下保存各租户子目录的目录，shardDir 会转义 @，ForKey 的目录不会与它重名
+const tenantDir = "@tenants"
+
+// tenantShard 返回租户 id 的日志文件相对于日志目录的子目录
+func tenantShard(id string) string {
+	return filepath.Join(tenantDir, shardDir(id))
+}
+
+// ForTenant 返回默认实例中租户 id 的一组日志记录器
+func ForTenant(id string) *contextLogger {
+	return std.ForTenant(id)
+}
+
+// ForTenant 返回租户 id 的一组日志记录器，日志只写入 @tenants 目录下以 id 命名的子目录中的文件，
+// 例如 logs/@tenants/acme/2006-01-02.info.log，id 按 ForKey 的规则转义，不同租户的目录互不相同，也不会与 ForKey 的目录相同，
+// 每条日志附加 tenant 字段以便附加的 writer 区分租户，级别、格式、轮转、切分、压缩和清理等配置与全局相同，
+// 不再写入某个租户时调用 CloseTenant 关闭它的文件
+func (l *Logger) ForTenant(id string) *contextLogger {
+	return l.forShard(tenantShard(id)).With(TenantKey, id)
+}
+
+// CloseTenant 关闭默认实例中租户 id 的日志文件
+func CloseTenant(id string) error {
+	return std.CloseTenant(id)
+}
+
+// CloseTenant 关闭租户 id 已打开的日志文件，之后再写入该租户时会重新打开
+func (l *Logger) CloseTenant(id string) error {
+	return l.closeShard(tenantShard(id))
+}
@@ -0,0 +1,30 @@
+package logger
+
+// DurationKey 是 Timed 输出的耗时字段名
+const DurationKey = "duration"
+
+// Timed 开始计时，调用返回的函数时输出 msg 并以 duration 字段记录经过的时间，常与 defer 一起使用：
+//
+//	defer logger.Info.Timed("load users")()
+//
+// 开始计时时该级别不需要输出则返回空函数
+func (l *logger) Timed(msg string) func() {
+	return (&fieldLogger{logger: l}).Timed(msg)
+}
+
+func (l *fieldLogger) Timed(msg string) func() {
+	if !l.logger.enabledFor(l.module) {
+		return func() {}
+	}
+	start := l.logger.owner.now()
+	return func() {
+		l.With(DurationKey, l.logger.owner.now().Sub(start)).Println(msg)
+	}
+}
+
+// TrackDuration 与 l.Timed(msg) 相同，l 可以是 logger.Info 或者 With、WithContext 返回的记录器：
+//
+//	defer logger.TrackDuration(logger.Info, "db.query")()
+func TrackDuration(l interface{ Timed(msg string) func() }, msg string) func() {
+	return l.Timed(msg)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import "time"
+
+// ErrorKey 是 Err 创建的字段名
+const ErrorKey = "error"
+
+// String 创建字符串类型的字段，与 WithFields 一起使用：
+//
+//	logger.Info.WithFields(logger.String("user", u), logger.Int("count", n)).Println("login")
+//
+// 类型化的字段在编码时直接写入，不需要 With 对键的 fmt.Sprint 和对值的反射
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int 创建整数类型的字段
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: int64(value)}
+}
+
+// Int64 创建 int64 类型的字段
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 创建 uint64 类型的字段
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 创建浮点数类型的字段
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool 创建布尔类型的字段
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration 创建时长类型的字段，输出为 1.5s 形式的文本
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time 创建时间类型的字段，值为 RFC3339 格式的文本
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value.Format(time.RFC3339Nano)}
+}
+
+// Err 创建键为 error 的字段，记录 err.Error()，err 为 nil 时值为 null
+func Err(err error) Field {
+	return Field{Key: ErrorKey, Value: err}
+}
+
+// Any 创建任意类型的字段，与 With 中的一个键值对相同
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// WithFields 附加类型化的字段，由 String、Int、Err 等函数创建
+func (l *logger) WithFields(fields ...Field) *fieldLogger {
+	return (&fieldLogger{logger: l}).WithFields(fields...)
+}
+
+func (l *errorLogger) WithFields(fields ...Field) *fieldLogger {
+	return (&fieldLogger{logger: &l.logger, expandErr: true}).WithFields(fields...)
+}
+
+func (l *fieldLogger) WithFields(fields ...Field) *fieldLogger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &fieldLogger{logger: l.logger, fields: merged, expandErr: l.expandErr, ctx: l.ctx, module: l.module, shard: l.shard}
+}
@@ -0,0 +1,363 @@
+// Package webhook 将 go_logger 中 ERROR 及以上级别的日志发送到 Slack、飞书、钉钉等机器人的 webhook，用于简单的告警。
+//
+// 每分钟最多发送 WithRateLimit 条消息，超出的日志会合并到下一条消息中，开启 logger.SetFingerprint 时
+// 同一条消息中指纹相同的日志只显示第一条和重复的次数；Fatalln 退出前会立即发送尚未发送的日志：
+//
+//	n := webhook.New("https://open.feishu.cn/open-apis/bot/v2/hook/xxx", webhook.WithFormat(webhook.Feishu))
+//	n.Attach(logger.Default())
+//	defer n.Close()
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nickham-su/go_logger"
+)
+
+// Format 是 webhook 请求体的格式
+type Format int
+
+const (
+	// Slack 发送 {"text": "..."}，也适用于 Mattermost、Rocket.Chat 等兼容的服务
+	Slack Format = iota
+	// Feishu 发送飞书机器人的文本消息
+	Feishu
+	// DingTalk 发送钉钉机器人的文本消息
+	DingTalk
+)
+
+// throttleWindow 是限制发送频率的时间窗口
+const throttleWindow = time.Minute
+
+// maxPending 是等待发送的最大日志条数，超出的日志只计数
+const maxPending = 1000
+
+// fingerprintRegexp 匹配文本、logfmt 和 JSON 格式中 logger.SetFingerprint 添加的指纹
+var fingerprintRegexp = regexp.MustCompile(logger.FingerprintKey + `(?:=|":")([0-9a-f]{16})`)
+
+// Option 用于 New 的配置项
+type Option func(*Notifier)
+
+// WithFormat 设置请求体的格式，默认为 Slack
+func WithFormat(f Format) Option {
+	return func(n *Notifier) {
+		n.payload = payloadFor(f)
+	}
+}
+
+// WithPayload 自定义请求体，text 为合并后的消息内容，返回值会编码为 JSON
+func WithPayload(f func(text string) interface{}) Option {
+	return func(n *Notifier) {
+		if f != nil {
+			n.payload = f
+		}
+	}
+}
+
+// WithTitle 设置消息的第一行，默认为主机名
+func WithTitle(title string) Option {
+	return func(n *Notifier) {
+		n.title = title
+	}
+}
+
+// WithMinLevel 设置发送的最低级别，默认为 ERROR
+func WithMinLevel(level logger.Level) Option {
+	return func(n *Notifier) {
+		n.minRank = level.Rank()
+	}
+}
+
+// WithRateLimit 设置每分钟最多发送的消息数，默认为 10
+func WithRateLimit(perMinute int) Option {
+	return func(n *Notifier) {
+		if perMinute > 0 {
+			n.limit = perMinute
+		}
+	}
+}
+
+// WithMaxLines 设置一条消息中最多包含的日志条数，其余的只显示条数，默认为 10
+func WithMaxLines(lines int) Option {
+	return func(n *Notifier) {
+		if lines > 0 {
+			n.maxLines = lines
+		}
+	}
+}
+
+// WithHTTPClient 设置发送请求使用的 http.Client，默认为超时 10 秒的 http.Client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(n *Notifier) {
+		if hc != nil {
+			n.http = hc
+		}
+	}
+}
+
+// WithErrorHandler 设置发送失败时的回调，默认忽略错误
+func WithErrorHandler(h func(err error)) Option {
+	return func(n *Notifier) {
+		n.onError = h
+	}
+}
+
+// Notifier 将日志合并后发送到 webhook
+type Notifier struct {
+	url      string
+	payload  func(text string) interface{}
+	title    string
+	minRank  int
+	limit    int
+	maxLines int
+	http     *http.Client
+	onError  func(err error)
+
+	mu      sync.Mutex
+	pending []pendingLine
+	// dropped 是等待发送的日志超过 maxPending 后未保存的条数
+	dropped int
+	// windowStart 是当前限流窗口的开始时间，sent 是窗口内已发送的消息数
+	windowStart time.Time
+	sent        int
+	// sendMu 保证消息按顺序发送
+	sendMu sync.Mutex
+
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// pendingLine 是等待发送的一条日志，count 是合并的指纹相同的日志条数
+type pendingLine struct {
+	line        string
+	fingerprint string
+	count       int
+}
+
+// New 创建向 url 发送告警的 Notifier
+func New(url string, opts ...Option) *Notifier {
+	n := &Notifier{
+		url:      url,
+		payload:  payloadFor(Slack),
+		minRank:  logger.LevelError.Rank(),
+		limit:    10,
+		maxLines: 10,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	n.title, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(n)
+	}
+	go n.run()
+	return n
+}
+
+// Attach 将 Notifier 添加到 l 中达到最低级别的每个级别
+func (n *Notifier) Attach(l *logger.Logger) {
+	for _, level := range logger.Levels() {
+		if level.Rank() >= n.minRank {
+			l.AppendWriterFor(level, n.Writer())
+		}
+	}
+}
+
+// Writer 返回接收日志的 io.Writer，用于 Logger.AppendWriterFor
+func (n *Notifier) Writer() io.Writer {
+	return &notifyWriter{n: n}
+}
+
+// Flush 不受频率限制，立即发送尚未发送的日志
+func (n *Notifier) Flush() error {
+	text, ok := n.take(true)
+	if !ok {
+		return nil
+	}
+	return n.send(text)
+}
+
+// Close 停止后台发送并立即发送剩余的日志
+func (n *Notifier) Close() error {
+	n.closeOnce.Do(func() {
+		close(n.stop)
+		<-n.done
+	})
+	return n.Flush()
+}
+
+func (n *Notifier) add(line string) {
+	var fp string
+	if m := fingerprintRegexp.FindStringSubmatch(line); m != nil {
+		fp = m[1]
+	}
+	n.mu.Lock()
+	n.addLocked(pendingLine{line: line, fingerprint: fp, count: 1})
+	n.mu.Unlock()
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// addLocked 加入一条日志，与等待发送的日志指纹相同时只增加计数，调用方需持有 n.mu
+func (n *Notifier) addLocked(p pendingLine) {
+	if p.fingerprint != "" {
+		for i := range n.pending {
+			if n.pending[i].fingerprint == p.fingerprint {
+				n.pending[i].count++
+				return
+			}
+		}
+	}
+	if len(n.pending) < maxPending {
+		n.pending = append(n.pending, p)
+	} else {
+		n.dropped++
+	}
+}
+
+// take 取出等待发送的日志并合并为一条消息，force 为 false 时受频率限制，超出限制时 ok 为 false
+func (n *Notifier) take(force bool) (text string, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.pending) == 0 {
+		return "", false
+	}
+	now := time.Now()
+	if now.Sub(n.windowStart) >= throttleWindow {
+		n.windowStart = now
+		n.sent = 0
+	}
+	if !force && n.sent >= n.limit {
+		return "", false
+	}
+	n.sent++
+	var b strings.Builder
+	if n.title != "" {
+		b.WriteString(n.title)
+		b.WriteByte('\n')
+	}
+	more := n.dropped
+	for i, p := range n.pending {
+		if i >= n.maxLines {
+			more += p.count
+			continue
+		}
+		b.WriteString(p.line)
+		if p.count > 1 {
+			b.WriteString(" (×" + strconv.Itoa(p.count) + ")")
+		}
+		b.WriteByte('\n')
+	}
+	if more > 0 {
+		fmt.Fprintf(&b, "……以及另外 %d 条日志\n", more)
+	}
+	n.pending = nil
+	n.dropped = 0
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
+
+// windowEnd 返回当前限流窗口结束的时间
+func (n *Notifier) windowEnd() time.Time {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.windowStart.Add(throttleWindow)
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-n.wake:
+		}
+		for {
+			if text, ok := n.take(false); ok {
+				_ = n.send(text)
+				break
+			}
+			// 超出频率限制，等到下一个窗口再把这段时间内的日志合并为一条发送
+			timer := time.NewTimer(time.Until(n.windowEnd()))
+			select {
+			case <-n.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func (n *Notifier) send(text string) error {
+	n.sendMu.Lock()
+	defer n.sendMu.Unlock()
+	err := n.post(text)
+	if err != nil && n.onError != nil {
+		n.onError(err)
+	}
+	return err
+}
+
+func (n *Notifier) post(text string) error {
+	body, err := json.Marshal(n.payload(text))
+	if err != nil {
+		return err
+	}
+	resp, err := n.http.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("发送告警失败：%s %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// payloadFor 返回内置格式的请求体
+func payloadFor(f Format) func(text string) interface{} {
+	switch f {
+	case Feishu:
+		return func(text string) interface{} {
+			return map[string]interface{}{"msg_type": "text", "content": map[string]string{"text": text}}
+		}
+	case DingTalk:
+		return func(text string) interface{} {
+			return map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": text}}
+		}
+	}
+	return func(text string) interface{} {
+		return map[string]string{"text": text}
+	}
+}
+
+// notifyWriter 将写入的每条日志加入等待发送的队列
+type notifyWriter struct {
+	n *Notifier
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	w.n.add(string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
+
+// Flush 使 Logger.Sync 能够立即发送，Fatalln 退出前会调用 Sync
+func (w *notifyWriter) Flush() error {
+	return w.n.Flush()
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// WorkerKey 是 WithWorker 附加的字段名
+const WorkerKey = "worker"
+
+// GoroutineKey 是 SetGoroutineID 添加的字段名
+const GoroutineKey = "goroutine"
+
+// WithWorker 返回默认实例中附加了 worker 字段的一组日志记录器
+func WithWorker(name string) *contextLogger {
+	return std.WithWorker(name)
+}
+
+// WithWorker 返回附加了 worker=name 字段的一组日志记录器，用于区分并发的 goroutine 交错写入同一个文件的日志，例如：
+//
+//	for i := 0; i < 20; i++ {
+//		log := logger.WithWorker(fmt.Sprintf("fetcher-%d", i))
+//		go func() { log.Info.Println("start") }()
+//	}
+func (l *Logger) WithWorker(name string) *contextLogger {
+	return l.With(WorkerKey, name)
+}
+
+// WithWorker 返回在当前键值对之后附加了 worker=name 字段的一组日志记录器，可以与 Named、ForKey 组合使用
+func (l *contextLogger) WithWorker(name string) *contextLogger {
+	return l.With(WorkerKey, name)
+}
+
+// SetGoroutineID 设置默认实例是否为每条日志添加 goroutine 字段
+func SetGoroutineID(enabled bool) {
+	std.SetGoroutineID(enabled)
+}
+
+// SetGoroutineID 设置是否为每条日志添加输出日志的 goroutine 的 ID，字段名为 goroutine，
+// 用于没有使用 WithWorker 命名的 goroutine，获取 ID 需要调用 runtime.Stack，默认关闭
+func (l *Logger) SetGoroutineID(enabled bool) {
+	l.goroutineID.Store(enabled)
+}
+
+// addGoroutineID 在开启 SetGoroutineID 时为日志添加 goroutine 字段
+func (l *Logger) addGoroutineID(e *Entry) {
+	if !l.goroutineID.Load() {
+		return
+	}
+	id, ok := goroutineID()
+	if !ok {
+		return
+	}
+	fields := make([]Field, len(e.Fields), len(e.Fields)+1)
+	copy(fields, e.Fields)
+	e.Fields = append(fields, Field{Key: GoroutineKey, Value: id})
+}
+
+// goroutineID 从 runtime.Stack 的第一行 "goroutine 18 [running]:" 中解析当前 goroutine 的 ID
+func goroutineID() (int64, bool) {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	return id, err == nil
+}
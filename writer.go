@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"io"
+	"strings"
+)
+
+// Writer 返回一个 io.Writer，每次 Write 的内容作为一条该级别的日志输出，结尾的换行符会被去掉，
+// 可用于 log.New、http.Server.ErrorLog 等只接受 io.Writer 的地方
+func (l *logger) Writer() io.Writer {
+	return levelWriter{logger: l}
+}
+
+type levelWriter struct {
+	logger *logger
+}
+
+func (w levelWriter) Write(p []byte) (int, error) {
+	if w.logger.enabled() {
+		w.logger.output(nil, strings.TrimRight(string(p), "\r\n"), nil, "")
+	}
+	return len(p), nil
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+var (
+	// ErrNilWriter 表示添加的 writer 为 nil
+	ErrNilWriter = errors.New("writer 不能为 nil")
+	// ErrDuplicateWriter 表示添加的 writer 已经添加过
+	ErrDuplicateWriter = errors.New("writer 已经添加过")
+)
+
+// RemoveWriter 从默认实例中移除 writer
+func RemoveWriter(w io.Writer) bool {
+	return std.RemoveWriter(w)
+}
+
+// RemoveWriter 移除通过 AppendWriter 或 AppendWriterFor 添加的 w，移除后不再向其写入，
+// 用于拆除临时添加的 writer，w 没有添加过时返回 false，移除不会关闭 w
+func (l *Logger) RemoveWriter(w io.Writer) bool {
+	l.mu.Lock()
+	var removed bool
+	l.writers, removed = removeWriter(l.writers, w)
+	levelWriters := make(map[Level][]io.Writer, len(l.levelWriters))
+	for level, writers := range l.levelWriters {
+		var ok bool
+		levelWriters[level], ok = removeWriter(writers, w)
+		removed = removed || ok
+	}
+	l.levelWriters = levelWriters
+	l.mu.Unlock()
+	if removed {
+		l.refreshWriters()
+	}
+	return removed
+}
+
+// removeWriter 返回去掉 w 之后的新切片，不修改 writers，正在写入的 multiWriter 可能仍在使用原来的切片
+func removeWriter(writers []io.Writer, w io.Writer) ([]io.Writer, bool) {
+	out := make([]io.Writer, 0, len(writers))
+	for _, existing := range writers {
+		if !sameWriter(unwrapWriter(existing), w) {
+			out = append(out, existing)
+		}
+	}
+	return out, len(out) != len(writers)
+}
+
+// validWriters 返回 writer 中可以添加的部分，跳过 nil 和已添加的 writer，level 为 nil 时检查所有级别，
+// 否则只检查接收所有级别的 writer 和 level 级别的 writer，调用方需持有 l.mu
+func (l *Logger) validWriters(writer []io.Writer, level *Level) ([]io.Writer, error) {
+	var existing []io.Writer
+	existing = append(existing, l.writers...)
+	if level != nil {
+		existing = append(existing, l.levelWriters[*level]...)
+	} else {
+		for _, writers := range l.levelWriters {
+			existing = append(existing, writers...)
+		}
+	}
+	var err error
+	valid := make([]io.Writer, 0, len(writer))
+	for _, w := range writer {
+		if w == nil || isNilPointer(w) {
+			if err == nil {
+				err = ErrNilWriter
+			}
+			continue
+		}
+		if containsWriter(existing, w) || containsWriter(valid, w) {
+			if err == nil {
+				err = ErrDuplicateWriter
+			}
+			continue
+		}
+		valid = append(valid, w)
+	}
+	return valid, err
+}
+
+func containsWriter(writers []io.Writer, w io.Writer) bool {
+	for _, existing := range writers {
+		if sameWriter(unwrapWriter(existing), w) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameWriter 判断两个 writer 是否相同，不可比较的类型（例如 func、slice）只在两者是同一个值时视为相同
+func sameWriter(a, b io.Writer) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta.Comparable() {
+		return a == b
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Func, reflect.Map, reflect.Slice:
+		return va.Pointer() == vb.Pointer()
+	}
+	return false
+}
+
+// isNilPointer 判断 w 是否为包装在接口中的 nil 指针
+func isNilPointer(w io.Writer) bool {
+	v := reflect.ValueOf(w)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// unwrapWriter 返回 lockWriters 包装之前的 writer
+func unwrapWriter(w io.Writer) io.Writer {
+	if lw, ok := w.(*lockedWriter); ok {
+		return lw.w
+	}
+	return w
+}
+
+func unwrapWriters(writers []io.Writer) []io.Writer {
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		out[i] = unwrapWriter(w)
+	}
+	return out
+}